@@ -0,0 +1,204 @@
+package cassandra
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include <cassandra.h>
+
+extern void go_auth_initial(CassAuthenticator* auth, uintptr_t handle);
+extern void go_auth_challenge(CassAuthenticator* auth, char* token, size_t size);
+extern void go_auth_success(CassAuthenticator* auth, char* token, size_t size);
+extern void go_auth_cleanup(CassAuthenticator* auth);
+extern void go_auth_data_cleanup(uintptr_t handle);
+
+static void auth_initial(CassAuthenticator* auth, void* data) {
+	go_auth_initial(auth, (uintptr_t)data);
+}
+
+static void auth_challenge(CassAuthenticator* auth, void* data, const char* token, size_t size) {
+	go_auth_challenge(auth, (char*)token, size);
+}
+
+static void auth_success(CassAuthenticator* auth, void* data, const char* token, size_t size) {
+	go_auth_success(auth, (char*)token, size);
+}
+
+static void auth_cleanup(CassAuthenticator* auth, void* data) {
+	go_auth_cleanup(auth);
+}
+
+static void auth_data_cleanup(void* data) {
+	go_auth_data_cleanup((uintptr_t)data);
+}
+
+static const CassAuthenticatorCallbacks auth_callbacks = {
+	auth_initial,
+	auth_challenge,
+	auth_success,
+	auth_cleanup
+};
+
+static CassError set_authenticator(CassCluster* cluster, uintptr_t handle) {
+	return cass_cluster_set_authenticator_callbacks(cluster, &auth_callbacks, auth_data_cleanup, (void*)handle);
+}
+
+static void set_exchange_handle(CassAuthenticator* auth, uintptr_t handle) {
+	cass_authenticator_set_exchange_data(auth, (void*)handle);
+}
+
+static uintptr_t exchange_handle(CassAuthenticator* auth) {
+	return (uintptr_t)cass_authenticator_exchange_data(auth);
+}
+*/
+import "C"
+import "runtime/cgo"
+import "unsafe"
+
+// Authenticator carries out one SASL-style authentication exchange with a
+// server. Its methods run on the driver's IO threads, so they should return
+// promptly. An error aborts the connection; when connecting a session it
+// surfaces as ErrBadCredentials.
+type Authenticator interface {
+	// InitialResponse returns the first token sent to the server.
+	InitialResponse() ([]byte, error)
+
+	// Challenge answers a challenge token sent by the server.
+	Challenge(token []byte) ([]byte, error)
+
+	// Success is called with the server's final token once authentication
+	// has succeeded.
+	Success(token []byte) error
+}
+
+// AuthInfo describes the server an Authenticator is about to log in to.
+type AuthInfo struct {
+	Address  string
+	Hostname string
+
+	// ClassName is the server's authenticator class, for example
+	// "org.apache.cassandra.auth.PasswordAuthenticator".
+	ClassName string
+}
+
+// PasswordAuthenticator is an Authenticator for the server's
+// PasswordAuthenticator. It sends the same token as SetCredentials and is
+// mostly useful as a starting point for custom schemes.
+type PasswordAuthenticator struct {
+	Username string
+	Password string
+}
+
+func (auth PasswordAuthenticator) InitialResponse() ([]byte, error) {
+	token := make([]byte, 0, len(auth.Username)+len(auth.Password)+2)
+	token = append(token, 0)
+	token = append(token, auth.Username...)
+	token = append(token, 0)
+	token = append(token, auth.Password...)
+	return token, nil
+}
+
+func (auth PasswordAuthenticator) Challenge(token []byte) ([]byte, error) {
+	return nil, nil
+}
+
+func (auth PasswordAuthenticator) Success(token []byte) error {
+	return nil
+}
+
+// SetCredentials logs in with the server's PasswordAuthenticator.
+func (cluster *Cluster) SetCredentials(username string, password string) {
+	username_cstr := C.CString(username)
+	defer C.free(unsafe.Pointer(username_cstr))
+	password_cstr := C.CString(password)
+	defer C.free(unsafe.Pointer(password_cstr))
+	C.cass_cluster_set_credentials(cluster.cptr, username_cstr, password_cstr)
+}
+
+// SetAuthenticator logs in with Authenticators made by new_authenticator,
+// which is called once for each connection the driver opens. It replaces
+// SetCredentials.
+func (cluster *Cluster) SetAuthenticator(new_authenticator func(AuthInfo) Authenticator) error {
+	handle := cgo.NewHandle(new_authenticator)
+	rc := C.set_authenticator(cluster.cptr, C.uintptr_t(handle))
+	if rc != C.CASS_OK {
+		handle.Delete()
+		return new_error(rc)
+	}
+	return nil
+}
+
+func auth_info(auth *C.CassAuthenticator) AuthInfo {
+	var info AuthInfo
+	var inet C.CassInet
+	var buf [C.CASS_INET_STRING_LENGTH]C.char
+	C.cass_authenticator_address(auth, &inet)
+	C.cass_inet_string(inet, &buf[0])
+	info.Address = C.GoString(&buf[0])
+
+	var length C.size_t
+	if str := C.cass_authenticator_hostname(auth, &length); str != nil {
+		info.Hostname = C.GoStringN(str, C.int(length))
+	}
+	if str := C.cass_authenticator_class_name(auth, &length); str != nil {
+		info.ClassName = C.GoStringN(str, C.int(length))
+	}
+	return info
+}
+
+func auth_respond(auth *C.CassAuthenticator, token []byte, err error) {
+	if err != nil {
+		msg := err.Error()
+		cs := C.CString(msg)
+		defer C.free(unsafe.Pointer(cs))
+		C.cass_authenticator_set_error_n(auth, cs, C.size_t(len(msg)))
+		return
+	}
+	C.cass_authenticator_set_response(auth, bytes_char_ptr(token), C.size_t(len(token)))
+}
+
+func exchange_authenticator(auth *C.CassAuthenticator) Authenticator {
+	handle := C.exchange_handle(auth)
+	if handle == 0 {
+		return nil
+	}
+	return cgo.Handle(handle).Value().(Authenticator)
+}
+
+func authenticator_initial(auth *C.CassAuthenticator, handle C.uintptr_t) {
+	new_authenticator := cgo.Handle(handle).Value().(func(AuthInfo) Authenticator)
+	authenticator := new_authenticator(auth_info(auth))
+	if authenticator == nil {
+		auth_respond(auth, nil, new_error(C.CASS_ERROR_SERVER_BAD_CREDENTIALS))
+		return
+	}
+	C.set_exchange_handle(auth, C.uintptr_t(cgo.NewHandle(authenticator)))
+
+	token, err := authenticator.InitialResponse()
+	auth_respond(auth, token, err)
+}
+
+func authenticator_challenge(auth *C.CassAuthenticator, token []byte) {
+	authenticator := exchange_authenticator(auth)
+	if authenticator == nil {
+		return
+	}
+	response, err := authenticator.Challenge(token)
+	auth_respond(auth, response, err)
+}
+
+func authenticator_success(auth *C.CassAuthenticator, token []byte) {
+	authenticator := exchange_authenticator(auth)
+	if authenticator == nil {
+		return
+	}
+	if err := authenticator.Success(token); err != nil {
+		auth_respond(auth, nil, err)
+	}
+}
+
+func authenticator_cleanup(auth *C.CassAuthenticator) {
+	if handle := C.exchange_handle(auth); handle != 0 {
+		cgo.Handle(handle).Delete()
+		C.set_exchange_handle(auth, 0)
+	}
+}
@@ -0,0 +1,122 @@
+package cassandra
+
+// #include <cassandra.h>
+import "C"
+import "context"
+import "sync"
+import "time"
+
+type BatchType int
+
+const (
+	CASS_BATCH_TYPE_LOGGED   BatchType = 0x00
+	CASS_BATCH_TYPE_UNLOGGED BatchType = 0x01
+	CASS_BATCH_TYPE_COUNTER  BatchType = 0x02
+)
+
+type Batch struct {
+	cptr *C.struct_CassBatch_
+
+	// mu guards cptr against Close while a deadline is being restored from
+	// a driver callback.
+	mu sync.Mutex
+}
+
+func NewBatch(kind BatchType) *Batch {
+	batch := new(Batch)
+	batch.cptr = C.cass_batch_new(C.CassBatchType(kind))
+	track(batch)
+	return batch
+}
+
+// Add appends statement to the batch. The batch keeps its own reference to
+// the statement, so the caller may close statement once it has been added.
+func (batch *Batch) Add(statement *Statement) error {
+	rc := C.cass_batch_add_statement(batch.cptr, statement.cptr)
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+func (batch *Batch) SetConsistency(consistency Consistency) error {
+	rc := C.cass_batch_set_consistency(batch.cptr, C.CassConsistency(consistency))
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+func (batch *Batch) SetSerialConsistency(consistency Consistency) error {
+	rc := C.cass_batch_set_serial_consistency(batch.cptr, C.CassConsistency(consistency))
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+// SetTimestamp sets the write time of every statement in the batch, in
+// microseconds since the epoch.
+func (batch *Batch) SetTimestamp(timestamp int64) error {
+	rc := C.cass_batch_set_timestamp(batch.cptr, C.cass_int64_t(timestamp))
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+func (batch *Batch) Close() error {
+	batch.mu.Lock()
+	defer batch.mu.Unlock()
+	if batch.cptr == nil {
+		return nil
+	}
+	C.cass_batch_free(batch.cptr)
+	batch.cptr = nil
+	untrack(batch)
+	return nil
+}
+
+func (batch *Batch) Finalize() {
+	batch.Close()
+}
+
+func (session *Session) ExecuteBatch(batch *Batch) *Future {
+	future := new(Future)
+	future.cptr = C.cass_session_execute_batch(session.cptr, batch.cptr)
+	track(future)
+	return future
+}
+
+// ExecuteBatchContext is the batch counterpart of ExecuteContext.
+func (session *Session) ExecuteBatchContext(ctx context.Context, batch *Batch) (*Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	restore := func() {}
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout := time.Until(deadline)
+		if timeout <= 0 {
+			return nil, context.DeadlineExceeded
+		}
+		C.cass_batch_set_request_timeout(batch.cptr, C.cass_uint64_t(timeout_ms(timeout)))
+		restore = batch.restore_timeout
+	}
+
+	future := session.ExecuteBatch(batch)
+	if err := await_then(ctx, future, restore); err != nil {
+		return nil, err
+	}
+	defer future.Close()
+	return future.Result(), nil
+}
+
+// restore_timeout puts back the cluster's request timeout. It may run after
+// the batch is closed.
+func (batch *Batch) restore_timeout() {
+	batch.mu.Lock()
+	defer batch.mu.Unlock()
+	if batch.cptr != nil {
+		C.cass_batch_set_request_timeout(batch.cptr, C.CASS_UINT64_MAX)
+	}
+}
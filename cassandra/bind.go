@@ -0,0 +1,126 @@
+package cassandra
+
+// #include <cassandra.h>
+import "C"
+import "math/big"
+import "net"
+import "reflect"
+import "time"
+
+// Bind, BindByName and collection elements all convert Go values the same
+// way, in bind_to. What differs is where the value goes, which is up to
+// the binder: a marker by position, a marker by name or the end of a
+// collection. A new Go type only needs a case in bind_to.
+
+type binder interface {
+	bind_null() error
+	bind_unset() error
+	bind_int8(v C.cass_int8_t) C.CassError
+	bind_int16(v C.cass_int16_t) C.CassError
+	bind_int32(v C.cass_int32_t) C.CassError
+	bind_int64(v C.cass_int64_t) C.CassError
+	bind_uint32(v C.cass_uint32_t) C.CassError
+	bind_float(v C.cass_float_t) C.CassError
+	bind_double(v C.cass_double_t) C.CassError
+	bind_bool(v C.cass_bool_t) C.CassError
+	bind_string(v string) C.CassError
+	bind_bytes(v []byte) C.CassError
+	bind_uuid(v C.struct_CassUuid_) C.CassError
+	bind_inet(v C.CassInet) C.CassError
+	bind_decimal(varint []byte, scale C.cass_int32_t) C.CassError
+	bind_duration(months, days C.cass_int32_t, nanos C.cass_int64_t) C.CassError
+	bind_collection(v interface{}) error
+}
+
+func bind_to(b binder, v interface{}) error {
+	var err C.CassError = C.CASS_OK
+
+	switch v := bind_arg(v).(type) {
+
+	case nil:
+		return b.bind_null()
+
+	case UnsetValue:
+		return b.bind_unset()
+
+	case int8:
+		err = b.bind_int8(C.cass_int8_t(v))
+
+	case int16:
+		err = b.bind_int16(C.cass_int16_t(v))
+
+	case int32:
+		err = b.bind_int32(C.cass_int32_t(v))
+
+	case int64:
+		err = b.bind_int64(C.cass_int64_t(v))
+
+	case float32:
+		err = b.bind_float(C.cass_float_t(v))
+
+	case float64:
+		err = b.bind_double(C.cass_double_t(v))
+
+	case bool:
+		err = b.bind_bool(cass_bool(v))
+
+	case string:
+		err = b.bind_string(v)
+
+	case []byte:
+		err = b.bind_bytes(v)
+
+	case Uuid:
+		err = b.bind_uuid(v.uuid)
+
+	case [16]byte:
+		err = b.bind_uuid(UuidFromBytes(v).uuid)
+
+	case net.IP:
+		inet, e := new_inet(v)
+		if e != nil {
+			return e
+		}
+		err = b.bind_inet(inet)
+
+	case time.Time:
+		err = b.bind_int64(timestamp_ms(v))
+
+	case Date:
+		err = b.bind_uint32(v.cass_date())
+
+	case time.Duration:
+		nanos, e := time_of_day(v)
+		if e != nil {
+			return e
+		}
+		err = b.bind_int64(nanos)
+
+	case CqlDuration:
+		err = b.bind_duration(C.cass_int32_t(v.Months), C.cass_int32_t(v.Days), C.cass_int64_t(v.Nanos))
+
+	case *big.Int:
+		err = b.bind_bytes(varint_bytes(v))
+
+	case Decimal:
+		err = b.bind_decimal(varint_bytes(v.unscaled()), C.cass_int32_t(v.Scale))
+
+	case *big.Float:
+		decimal, e := float_decimal(v)
+		if e != nil {
+			return e
+		}
+		err = b.bind_decimal(varint_bytes(decimal.unscaled()), C.cass_int32_t(decimal.Scale))
+
+	default:
+		if !is_collection_value(v) {
+			return new_error_with_message(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE, "unsupported type in Bind: "+reflect.TypeOf(v).String())
+		}
+		return b.bind_collection(v)
+	}
+
+	if err != C.CASS_OK {
+		return new_error(err)
+	}
+	return nil
+}
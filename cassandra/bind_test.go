@@ -0,0 +1,19 @@
+package cassandra
+
+import "testing"
+
+func TestBindSmallInts(t *testing.T) {
+	statement := NewStatement("INSERT INTO readings (id, level, flags) VALUES (?, ?, ?)", 3)
+	defer statement.Close()
+
+	if err := statement.Bind(int32(1), int16(-300), int8(7)); err != nil {
+		t.Fatalf("Bind of smallint and tinyint values: %v", err)
+	}
+	level, flags := int16(12), int8(-1)
+	if err := statement.Bind(int32(1), &level, &flags); err != nil {
+		t.Fatalf("Bind of smallint and tinyint pointers: %v", err)
+	}
+	if err := statement.Bind(int32(1), []int16{1, 2}, map[int8]int16{1: 2}); err != nil {
+		t.Fatalf("Bind of smallint and tinyint collections: %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package cassandra
+
+import "container/list"
+import "context"
+import "errors"
+import "sync"
+import "sync/atomic"
+
+// Session.Query prepares each distinct CQL string once per session and
+// keeps the result. Concurrent callers asking for the same string while it
+// is being prepared wait for that one preparation instead of starting
+// their own. The cache holds at most Cluster.SetPreparedCacheSize
+// statements, evicting the least recently used beyond that. Cached
+// statements are freed when evicted or when the session is closed, once
+// nobody is still binding them.
+
+const default_prepared_cache_size = 1000
+
+type prepared_cache struct {
+	mu      sync.Mutex
+	entries map[string]*prepared_entry
+	closed  bool
+
+	// lru orders the entries from most to least recently used. size caps
+	// its length; zero means default_prepared_cache_size.
+	lru  list.List
+	size int
+
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+type prepared_entry struct {
+	cql      string
+	element  *list.Element
+	ready    chan struct{}
+	prepared *Prepared
+	err      error
+
+	// users counts callers still using prepared. An entry that has been
+	// dropped from the cache is freed once the last of them is done. Both
+	// fields are guarded by the cache's mutex.
+	users   int
+	dropped bool
+}
+
+// Query prepares cql (or reuses the cached preparation), binds args and
+// executes it. A statement the server reports as unprepared is evicted,
+// prepared again and retried once.
+func (session *Session) Query(cql string, args ...interface{}) (*Result, error) {
+	return session.QueryContext(context.Background(), cql, args...)
+}
+
+func (session *Session) QueryContext(ctx context.Context, cql string, args ...interface{}) (*Result, error) {
+	for attempt := 0; ; attempt++ {
+		prepared, release, err := session.cached_prepare(ctx, cql)
+		if err != nil {
+			return nil, err
+		}
+
+		statement := prepared.Bind()
+		session.mark_idempotent(statement)
+		if err := statement.Bind(args...); err != nil {
+			statement.Close()
+			release()
+			return nil, err
+		}
+		result, err := session.ExecuteContext(ctx, statement)
+		statement.Close()
+		release()
+
+		if attempt == 0 && errors.Is(err, ErrUnprepared) {
+			session.cache.evict(cql, prepared)
+			continue
+		}
+		return result, err
+	}
+}
+
+// cached_prepare returns the cached preparation of cql, preparing it first
+// if need be. The caller must call release once it no longer uses the
+// Prepared.
+func (session *Session) cached_prepare(ctx context.Context, cql string) (prepared *Prepared, release func(), err error) {
+	cache := &session.cache
+
+	for {
+		entry, ok := cache.lookup(cql)
+		if !ok {
+			atomic.AddInt64(&cache.misses, 1)
+			// Prepare independently of ctx: other callers may be waiting on
+			// this entry with contexts of their own.
+			go session.fill(cql, entry)
+		}
+
+		select {
+		case <-entry.ready:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+		if entry.err != nil {
+			// A failed preparation is a miss for everyone who waited on it.
+			if ok {
+				atomic.AddInt64(&cache.misses, 1)
+			}
+			return nil, nil, entry.err
+		}
+
+		cache.mu.Lock()
+		if entry.dropped {
+			// Evicted and possibly freed since it became ready; look again.
+			cache.mu.Unlock()
+			continue
+		}
+		entry.users++
+		cache.mu.Unlock()
+
+		if ok {
+			atomic.AddInt64(&cache.hits, 1)
+		}
+		return entry.prepared, func() { cache.release(entry) }, nil
+	}
+}
+
+// lookup returns the entry for cql, and whether it was already cached. A
+// new entry is not ready until filled.
+func (cache *prepared_cache) lookup(cql string) (*prepared_entry, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.entries == nil {
+		cache.entries = make(map[string]*prepared_entry)
+	}
+	entry, ok := cache.entries[cql]
+	if ok {
+		cache.lru.MoveToFront(entry.element)
+		return entry, true
+	}
+	entry = &prepared_entry{cql: cql, ready: make(chan struct{})}
+	entry.element = cache.lru.PushFront(entry)
+	cache.entries[cql] = entry
+	cache.trim()
+	return entry, false
+}
+
+func (session *Session) fill(cql string, entry *prepared_entry) {
+	prepared, err := session.PrepareContext(context.Background(), cql)
+
+	cache := &session.cache
+	cache.mu.Lock()
+	entry.prepared, entry.err = prepared, err
+	if err != nil {
+		// Failures are not cached; the next caller tries again.
+		if cache.entries[cql] == entry {
+			cache.remove(entry)
+		}
+	} else if cache.closed {
+		cache.drop(entry)
+	}
+	cache.mu.Unlock()
+	close(entry.ready)
+}
+
+func (cache *prepared_cache) release(entry *prepared_entry) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry.users--
+	if entry.dropped && entry.users == 0 {
+		entry.prepared.Close()
+	}
+}
+
+// drop marks entry as no longer cached and frees it unless it is in use,
+// in which case the last release frees it. The caller holds cache.mu.
+func (cache *prepared_cache) drop(entry *prepared_entry) {
+	entry.dropped = true
+	if entry.users == 0 {
+		entry.prepared.Close()
+	}
+}
+
+// evict drops prepared from the cache if it is still the cached entry for
+// cql.
+func (cache *prepared_cache) evict(cql string, prepared *Prepared) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[cql]
+	if !ok {
+		return
+	}
+	select {
+	case <-entry.ready:
+	default:
+		return
+	}
+	if entry.prepared != prepared {
+		return
+	}
+	cache.remove(entry)
+	cache.drop(entry)
+	atomic.AddInt64(&cache.evictions, 1)
+}
+
+// trim evicts the least recently used entries until the cache is back
+// within its size. Entries still being prepared are left alone. The caller
+// holds cache.mu.
+func (cache *prepared_cache) trim() {
+	size := cache.size
+	if size <= 0 {
+		size = default_prepared_cache_size
+	}
+
+	element := cache.lru.Back()
+	for cache.lru.Len() > size && element != nil {
+		entry := element.Value.(*prepared_entry)
+		element = element.Prev()
+		select {
+		case <-entry.ready:
+		default:
+			continue
+		}
+		cache.remove(entry)
+		cache.drop(entry)
+		atomic.AddInt64(&cache.evictions, 1)
+	}
+}
+
+// remove takes entry out of the cache without freeing it. The caller holds
+// cache.mu.
+func (cache *prepared_cache) remove(entry *prepared_entry) {
+	delete(cache.entries, entry.cql)
+	cache.lru.Remove(entry.element)
+}
+
+// close drops every entry. Preparations still in flight are freed when
+// they complete.
+func (cache *prepared_cache) close() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.closed = true
+	for _, entry := range cache.entries {
+		select {
+		case <-entry.ready:
+			cache.drop(entry)
+		default:
+		}
+		cache.remove(entry)
+	}
+}
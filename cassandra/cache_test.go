@@ -0,0 +1,57 @@
+package cassandra
+
+import "reflect"
+import "sort"
+import "testing"
+
+func TestPreparedCacheLRU(t *testing.T) {
+	cache := &prepared_cache{size: 2}
+	add := func(cql string) *prepared_entry {
+		entry, ok := cache.lookup(cql)
+		if ok {
+			t.Fatalf("lookup(%q) found an entry that was never added", cql)
+		}
+		entry.prepared = new(Prepared)
+		close(entry.ready)
+		return entry
+	}
+	cached := func() []string {
+		var keys []string
+		for cql := range cache.entries {
+			keys = append(keys, cql)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	a := add("a")
+	b := add("b")
+	if _, ok := cache.lookup("a"); !ok {
+		t.Fatal("lookup(a) missed")
+	}
+	add("c")
+
+	if got, want := cached(), []string{"a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("cached = %v, want %v", got, want)
+	}
+	if !b.dropped || a.dropped {
+		t.Errorf("dropped a=%v b=%v, want only b", a.dropped, b.dropped)
+	}
+	if cache.evictions != 1 {
+		t.Errorf("evictions = %d, want 1", cache.evictions)
+	}
+
+	// Entries still being prepared are not evicted.
+	d, _ := cache.lookup("d")
+	e, _ := cache.lookup("e")
+	if got, want := cached(), []string{"d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("cached = %v, want %v", got, want)
+	}
+	f, _ := cache.lookup("f")
+	if got, want := cached(), []string{"d", "e", "f"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("cached = %v, want %v", got, want)
+	}
+	for _, entry := range []*prepared_entry{d, e, f} {
+		close(entry.ready)
+	}
+}
@@ -5,9 +5,14 @@ package cassandra
 // #include <stdlib.h>
 // #include <cassandra.h>
 import "C"
+import "context"
+import "time"
+import "math/big"
+import "net"
 import "unsafe"
-import "errors"
 import "reflect"
+import "sync"
+import "sync/atomic"
 
 const (
 	CASS_OK = 0
@@ -86,11 +91,57 @@ const (
 	CASS_VALUE_TYPE_VARINT    = 0x000E
 	CASS_VALUE_TYPE_TIMEUUID  = 0x000F
 	CASS_VALUE_TYPE_INET      = 0x0010
+	CASS_VALUE_TYPE_DATE      = 0x0011
+	CASS_VALUE_TYPE_TIME      = 0x0012
+	CASS_VALUE_TYPE_SMALL_INT = 0x0013
+	CASS_VALUE_TYPE_TINY_INT  = 0x0014
+	CASS_VALUE_TYPE_DURATION  = 0x0015
 	CASS_VALUE_TYPE_LIST      = 0x0020
 	CASS_VALUE_TYPE_MAP       = 0x0021
 	CASS_VALUE_TYPE_SET       = 0x0022
+	CASS_VALUE_TYPE_UDT       = 0x0030
+	CASS_VALUE_TYPE_TUPLE     = 0x0031
 )
 
+var value_type_names = map[int]string{
+	CASS_VALUE_TYPE_CUSTOM:    "custom",
+	CASS_VALUE_TYPE_ASCII:     "ascii",
+	CASS_VALUE_TYPE_BIGINT:    "bigint",
+	CASS_VALUE_TYPE_BLOB:      "blob",
+	CASS_VALUE_TYPE_BOOLEAN:   "boolean",
+	CASS_VALUE_TYPE_COUNTER:   "counter",
+	CASS_VALUE_TYPE_DECIMAL:   "decimal",
+	CASS_VALUE_TYPE_DOUBLE:    "double",
+	CASS_VALUE_TYPE_FLOAT:     "float",
+	CASS_VALUE_TYPE_INT:       "int",
+	CASS_VALUE_TYPE_TEXT:      "text",
+	CASS_VALUE_TYPE_TIMESTAMP: "timestamp",
+	CASS_VALUE_TYPE_UUID:      "uuid",
+	CASS_VALUE_TYPE_VARCHAR:   "varchar",
+	CASS_VALUE_TYPE_VARINT:    "varint",
+	CASS_VALUE_TYPE_TIMEUUID:  "timeuuid",
+	CASS_VALUE_TYPE_INET:      "inet",
+	CASS_VALUE_TYPE_DATE:      "date",
+	CASS_VALUE_TYPE_TIME:      "time",
+	CASS_VALUE_TYPE_SMALL_INT: "smallint",
+	CASS_VALUE_TYPE_TINY_INT:  "tinyint",
+	CASS_VALUE_TYPE_DURATION:  "duration",
+	CASS_VALUE_TYPE_LIST:      "list",
+	CASS_VALUE_TYPE_MAP:       "map",
+	CASS_VALUE_TYPE_SET:       "set",
+	CASS_VALUE_TYPE_UDT:       "udt",
+	CASS_VALUE_TYPE_TUPLE:     "tuple",
+}
+
+// ValueTypeName returns the CQL name of one of the CASS_VALUE_TYPE_*
+// constants, such as "int" or "list".
+func ValueTypeName(vtype int) string {
+	if name, ok := value_type_names[vtype]; ok {
+		return name
+	}
+	return "unknown"
+}
+
 const (
 	CASS_LOG_DISABLED = iota
 	CASS_LOG_CRITICAL
@@ -103,27 +154,65 @@ const (
 
 type Cluster struct {
 	cptr *C.struct_CassCluster_
+
+	retry      RetryPolicy
+	idempotent bool
+	cache_size int
 }
 
 type Future struct {
 	cptr *C.struct_CassFuture_
+
+	mu        sync.Mutex
+	watching  bool
+	completed bool
+	closed    bool
+	done      chan struct{}
+	callbacks []func(*Future)
+
+	// table is what a SELECT reads from, for Result.Columns.
+	table table_name
+
+	// chained futures are made by execute_retry. They have no driver future
+	// of their own until the last attempt completes and hands over its own.
+	chained bool
 }
 
 type Session struct {
 	cptr *C.struct_CassSession_
+	mu   sync.Mutex
+
+	cache      prepared_cache
+	retry      RetryPolicy
+	idempotent bool
+	keyspace   string
 }
 
 type Result struct {
-	iter *C.struct_CassIterator_
-	cptr *C.struct_CassResult_
+	iter  *C.struct_CassIterator_
+	cptr  *C.struct_CassResult_
+	table table_name
 }
 
 type Prepared struct {
-	cptr *C.struct_CassPrepared_
+	cptr  *C.struct_CassPrepared_
+	table table_name
 }
 
 type Statement struct {
-	cptr *C.struct_CassStatement_
+	cptr     *C.struct_CassStatement_
+	prepared *Prepared
+	names    []string
+	table    table_name
+
+	retry          RetryPolicy
+	idempotent     bool
+	idempotent_set bool
+	timeout        time.Duration
+
+	// mu guards cptr against Close while a deadline is being restored from
+	// a driver callback.
+	mu sync.Mutex
 }
 
 type Uuid struct {
@@ -164,6 +253,12 @@ type Metrics struct {
 		PendingRequestTimeouts int64
 		RequestTimeouts        int64
 	}
+
+	PreparedCache struct {
+		Hits      int64
+		Misses    int64
+		Evictions int64
+	}
 }
 
 func SetLogLevel(level int32) {
@@ -190,7 +285,7 @@ func SetLogLevel(level int32) {
 func NewCluster() *Cluster {
 	cluster := new(Cluster)
 	cluster.cptr = C.cass_cluster_new()
-	// defer cluster.Finalize()
+	track(cluster)
 
 	return cluster
 }
@@ -198,6 +293,7 @@ func NewCluster() *Cluster {
 func NewSession() *Session {
 	session := new(Session)
 	session.cptr = C.cass_session_new()
+	track(session)
 	return session
 }
 
@@ -214,6 +310,7 @@ func (session *Session) Metrics() Metrics {
 	output.Requests.Median = int64(cmetrics.requests.median)
 	output.Requests.Percentile75th = int64(cmetrics.requests.percentile_75th)
 	output.Requests.Percentile95th = int64(cmetrics.requests.percentile_95th)
+	output.Requests.Percentile98th = int64(cmetrics.requests.percentile_98th)
 	output.Requests.Percentile99th = int64(cmetrics.requests.percentile_99th)
 	output.Requests.Percentile999th = int64(cmetrics.requests.percentile_999th)
 	output.Requests.MeanRate = float64(cmetrics.requests.mean_rate)
@@ -230,6 +327,10 @@ func (session *Session) Metrics() Metrics {
 	output.Errors.PendingRequestTimeouts = int64(cmetrics.errors.pending_request_timeouts)
 	output.Errors.RequestTimeouts = int64(cmetrics.errors.request_timeouts)
 
+	output.PreparedCache.Hits = atomic.LoadInt64(&session.cache.hits)
+	output.PreparedCache.Misses = atomic.LoadInt64(&session.cache.misses)
+	output.PreparedCache.Evictions = atomic.LoadInt64(&session.cache.evictions)
+
 	return output
 }
 
@@ -239,18 +340,23 @@ func NewStatement(query string, param_count int) *Statement {
 
 	statement := new(Statement)
 	statement.cptr = C.cass_statement_new(cs, C.size_t(param_count))
+	statement.names = parse_markers(query)
+	statement.table = select_table(query)
+	track(statement)
 	return statement
 }
 
 func NewUuidGenerator() *UuidGenerator {
 	generator := new(UuidGenerator)
 	generator.cptr = C.cass_uuid_gen_new()
+	track(generator)
 	return generator
 }
 
 func NewUuidGeneratorWithNode(node uint64) *UuidGenerator {
 	generator := new(UuidGenerator)
 	generator.cptr = C.cass_uuid_gen_new_with_node(C.cass_uint64_t(node))
+	track(generator)
 	return generator
 }
 
@@ -275,260 +381,242 @@ func (generator *UuidGenerator) FromTime(timestamp uint64) Uuid {
 func (prepared *Prepared) Bind() *Statement {
 	statement := new(Statement)
 	statement.cptr = C.cass_prepared_bind(prepared.cptr)
-	// defer statement.Finalize()
+	statement.prepared = prepared
+	statement.table = prepared.table
+	track(statement)
 	return statement
 }
 
-func (statement *Statement) Bind(args ...interface{}) error {
-	var err C.CassError = C.CASS_OK
+// ResetParameters unsets every bind marker and resizes the statement to
+// count markers, so that it can be bound again from scratch.
+func (statement *Statement) ResetParameters(count int) error {
+	rc := C.cass_statement_reset_parameters(statement.cptr, C.size_t(count))
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
 
+func (statement *Statement) Bind(args ...interface{}) error {
 	for i, v := range args {
+		if err := bind_value(statement, i, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		switch v := v.(type) {
-
-		case nil:
-			err = C.cass_statement_bind_null(statement.cptr, C.size_t(i))
+func bind_value(statement *Statement, index int, v interface{}) error {
+	return bind_to(index_binder{statement, C.size_t(index)}, v)
+}
 
-		case int32:
-			err = C.cass_statement_bind_int32(statement.cptr, C.size_t(i), C.cass_int32_t(v))
+// index_binder binds to the marker at index.
+type index_binder struct {
+	statement *Statement
+	index     C.size_t
+}
 
-		case int64:
-			err = C.cass_statement_bind_int64(statement.cptr, C.size_t(i), C.cass_int64_t(v))
+func (b index_binder) bind_null() error {
+	if rc := C.cass_statement_bind_null(b.statement.cptr, b.index); rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
 
-		case float32:
-			err = C.cass_statement_bind_float(statement.cptr, C.size_t(i), C.cass_float_t(v))
+func (b index_binder) bind_unset() error {
+	return nil
+}
 
-		case float64:
-			err = C.cass_statement_bind_double(statement.cptr, C.size_t(i), C.cass_double_t(v))
+func (b index_binder) bind_int8(v C.cass_int8_t) C.CassError {
+	return C.cass_statement_bind_int8(b.statement.cptr, b.index, v)
+}
 
-		case bool:
-			if v {
-				err = C.cass_statement_bind_bool(statement.cptr, C.size_t(i), 1)
-			} else {
-				err = C.cass_statement_bind_bool(statement.cptr, C.size_t(i), 0)
-			}
+func (b index_binder) bind_int16(v C.cass_int16_t) C.CassError {
+	return C.cass_statement_bind_int16(b.statement.cptr, b.index, v)
+}
 
-		case string:
-			err = bind_string(statement, i, v)
+func (b index_binder) bind_int32(v C.cass_int32_t) C.CassError {
+	return C.cass_statement_bind_int32(b.statement.cptr, b.index, v)
+}
 
-		case []byte:
-			err = C.cass_statement_bind_bytes(statement.cptr, C.size_t(i), (*C.cass_byte_t)(unsafe.Pointer(&v)), C.size_t(len(v)))
+func (b index_binder) bind_int64(v C.cass_int64_t) C.CassError {
+	return C.cass_statement_bind_int64(b.statement.cptr, b.index, v)
+}
 
-		case Uuid:
-			C.cass_statement_bind_uuid(statement.cptr, C.size_t(i), v.uuid)
-		}
+func (b index_binder) bind_uint32(v C.cass_uint32_t) C.CassError {
+	return C.cass_statement_bind_uint32(b.statement.cptr, b.index, v)
+}
 
-	}
+func (b index_binder) bind_float(v C.cass_float_t) C.CassError {
+	return C.cass_statement_bind_float(b.statement.cptr, b.index, v)
+}
 
-	if err != C.CASS_OK {
-		return errors.New(C.GoString(C.cass_error_desc(err)))
-	}
+func (b index_binder) bind_double(v C.cass_double_t) C.CassError {
+	return C.cass_statement_bind_double(b.statement.cptr, b.index, v)
+}
 
-	return nil
+func (b index_binder) bind_bool(v C.cass_bool_t) C.CassError {
+	return C.cass_statement_bind_bool(b.statement.cptr, b.index, v)
 }
 
-func bind_string(statement *Statement, index int, v string) C.CassError {
+func (b index_binder) bind_string(v string) C.CassError {
 	cs := C.CString(v)
 	defer C.free(unsafe.Pointer(cs))
-	return C.cass_statement_bind_string(statement.cptr, C.size_t(index), C.CString(v))
+	return C.cass_statement_bind_string_n(b.statement.cptr, b.index, cs, C.size_t(len(v)))
+}
+
+func (b index_binder) bind_bytes(v []byte) C.CassError {
+	return C.cass_statement_bind_bytes(b.statement.cptr, b.index, bytes_ptr(v), C.size_t(len(v)))
 }
 
+func (b index_binder) bind_uuid(v C.struct_CassUuid_) C.CassError {
+	return C.cass_statement_bind_uuid(b.statement.cptr, b.index, v)
+}
+
+func (b index_binder) bind_inet(v C.CassInet) C.CassError {
+	return C.cass_statement_bind_inet(b.statement.cptr, b.index, v)
+}
+
+func (b index_binder) bind_decimal(varint []byte, scale C.cass_int32_t) C.CassError {
+	return C.cass_statement_bind_decimal(b.statement.cptr, b.index, bytes_ptr(varint), C.size_t(len(varint)), scale)
+}
+
+func (b index_binder) bind_duration(months, days C.cass_int32_t, nanos C.cass_int64_t) C.CassError {
+	return C.cass_statement_bind_duration(b.statement.cptr, b.index, months, days, nanos)
+}
+
+func (b index_binder) bind_collection(v interface{}) error {
+	return bind_collection(b.statement, int(b.index), v)
+}
+
+func cass_bool(b bool) C.cass_bool_t {
+	if b {
+		return C.cass_true
+	}
+	return C.cass_false
+}
+
+// copy_bytes copies a value owned by the driver into dst, reusing dst's
+// backing array when it is large enough. An empty value gives an empty,
+// non-nil slice, so that it is not mistaken for NULL.
+func copy_bytes(dst []byte, src *C.cass_byte_t, length C.size_t) []byte {
+	n := int(length)
+	if dst == nil || cap(dst) < n {
+		dst = make([]byte, n)
+	} else {
+		dst = dst[:n]
+	}
+	if n > 0 {
+		copy(dst, unsafe.Slice((*byte)(unsafe.Pointer(src)), n))
+	}
+	return dst
+}
+
+// Finalize is kept for existing callers; it is the same as Close.
 func (cluster *Cluster) Finalize() {
-	C.cass_cluster_free(cluster.cptr)
-	cluster.cptr = nil
+	cluster.Close()
 }
 
 func (session *Session) Finalize() {
-	C.cass_session_free(session.cptr)
-	session.cptr = nil
+	session.Close()
 }
 
 func (future *Future) Finalize() {
-	C.cass_future_free(future.cptr)
-	future.cptr = nil
+	future.Close()
 }
 
 func (result *Result) Finalize() {
-	C.cass_result_free(result.cptr)
-	result.cptr = nil
+	result.Close()
 }
 
 func (prepared *Prepared) Finalize() {
-	C.cass_prepared_free(prepared.cptr)
-	prepared.cptr = nil
+	prepared.Close()
 }
 
 func (statement *Statement) Finalize() {
-	C.cass_statement_free(statement.cptr)
-	statement.cptr = nil
+	statement.Close()
 }
 
 func (generator *UuidGenerator) Finalize() {
-	C.cass_uuid_gen_free(generator.cptr)
-	generator.cptr = nil
+	generator.Close()
 }
 
 func (future *Future) Result() *Result {
+	future.wait()
 	result := new(Result)
 	result.cptr = C.cass_future_get_result(future.cptr)
-	// defer result.Finalize()
+	result.table = future.table
+	track(result)
 	return result
 }
 
 func (future *Future) Prepared() *Prepared {
+	future.wait()
 	prepared := new(Prepared)
 	prepared.cptr = C.cass_future_get_prepared(future.cptr)
-	// defer prepared.Finalize()
+	prepared.table = future.table
+	track(prepared)
 	return prepared
 }
 
 func (future *Future) Ready() bool {
+	if future.chained {
+		future.mu.Lock()
+		defer future.mu.Unlock()
+		return future.completed
+	}
 	return C.cass_future_ready(future.cptr) == C.cass_true
 }
 
 func (future *Future) Wait() {
+	if future.chained {
+		<-future.Done()
+		return
+	}
 	C.cass_future_wait(future.cptr)
 }
 
+// WaitTimed waits up to timeout microseconds and reports whether the future
+// completed.
 func (future *Future) WaitTimed(timeout uint64) bool {
+	if future.chained {
+		select {
+		case <-future.Done():
+			return true
+		case <-time.After(time.Duration(timeout) * time.Microsecond):
+			return false
+		}
+	}
 	return C.cass_future_wait_timed(future.cptr, C.cass_duration_t(timeout)) == C.cass_true
 }
 
 func (future *Future) ErrorMessage() string {
+	future.wait()
 	var message *C.char
 	var message_length C.size_t
 	C.cass_future_error_message(future.cptr, &message, &message_length)
-	return C.GoString(message)
+	return C.GoStringN(message, C.int(message_length))
 }
 
 func (future *Future) ErrorSource() int {
-	rc := C.cass_future_error_code(future.cptr)
-	source := (rc >> 24)
-
-	switch source {
-	case C.CASS_ERROR_SOURCE_NONE:
-		return CASS_OK
-	case C.CASS_ERROR_SOURCE_LIB:
-		return CASS_ERROR_SOURCE_LIB
-	case C.CASS_ERROR_SOURCE_SERVER:
-		return CASS_ERROR_SOURCE_SERVER
-	case C.CASS_ERROR_SOURCE_SSL:
-		return CASS_ERROR_SOURCE_SSL
-	case C.CASS_ERROR_SOURCE_COMPRESSION:
-		return CASS_ERROR_SOURCE_COMPRESSION
-	}
-	return CASS_ERROR_SOURCE_NONE
+	future.wait()
+	return error_source(C.cass_future_error_code(future.cptr))
 }
 
 func (future *Future) ErrorCode() int {
+	future.wait()
+	return error_code(C.cass_future_error_code(future.cptr))
+}
+
+// Err waits for the future and returns its error, or nil if the request
+// succeeded.
+func (future *Future) Err() error {
+	future.wait()
 	rc := C.cass_future_error_code(future.cptr)
-	source := future.ErrorSource()
-
-	if source == CASS_ERROR_SOURCE_NONE {
-		return CASS_OK
-	} else if source == CASS_ERROR_SOURCE_LIB {
-		switch rc {
-		case CASS_OK:
-			return CASS_OK
-		case C.CASS_ERROR_LIB_BAD_PARAMS:
-			return CASS_ERROR_LIB_BAD_PARAMS
-		case C.CASS_ERROR_LIB_NO_STREAMS:
-			return CASS_ERROR_LIB_NO_STREAMS
-		case C.CASS_ERROR_LIB_UNABLE_TO_INIT:
-			return CASS_ERROR_LIB_UNABLE_TO_INIT
-		case C.CASS_ERROR_LIB_MESSAGE_ENCODE:
-			return CASS_ERROR_LIB_MESSAGE_ENCODE
-		case C.CASS_ERROR_LIB_HOST_RESOLUTION:
-			return CASS_ERROR_LIB_HOST_RESOLUTION
-		case C.CASS_ERROR_LIB_UNEXPECTED_RESPONSE:
-			return CASS_ERROR_LIB_UNEXPECTED_RESPONSE
-		case C.CASS_ERROR_LIB_REQUEST_QUEUE_FULL:
-			return CASS_ERROR_LIB_REQUEST_QUEUE_FULL
-		case C.CASS_ERROR_LIB_NO_AVAILABLE_IO_THREAD:
-			return CASS_ERROR_LIB_NO_AVAILABLE_IO_THREAD
-		case C.CASS_ERROR_LIB_WRITE_ERROR:
-			return CASS_ERROR_LIB_WRITE_ERROR
-		case C.CASS_ERROR_LIB_NO_HOSTS_AVAILABLE:
-			return CASS_ERROR_LIB_NO_HOSTS_AVAILABLE
-		case C.CASS_ERROR_LIB_INDEX_OUT_OF_BOUNDS:
-			return CASS_ERROR_LIB_INDEX_OUT_OF_BOUNDS
-		case C.CASS_ERROR_LIB_INVALID_ITEM_COUNT:
-			return CASS_ERROR_LIB_INVALID_ITEM_COUNT
-		case C.CASS_ERROR_LIB_INVALID_VALUE_TYPE:
-			return CASS_ERROR_LIB_INVALID_VALUE_TYPE
-		case C.CASS_ERROR_LIB_REQUEST_TIMED_OUT:
-			return CASS_ERROR_LIB_REQUEST_TIMED_OUT
-		case C.CASS_ERROR_LIB_UNABLE_TO_SET_KEYSPACE:
-			return CASS_ERROR_LIB_UNABLE_TO_SET_KEYSPACE
-		case C.CASS_ERROR_LIB_CALLBACK_ALREADY_SET:
-			return CASS_ERROR_LIB_UNABLE_TO_SET_KEYSPACE
-		case C.CASS_ERROR_LIB_INVALID_STATEMENT_TYPE:
-			return CASS_ERROR_LIB_INVALID_STATEMENT_TYPE
-		case C.CASS_ERROR_LIB_NAME_DOES_NOT_EXIST:
-			return CASS_ERROR_LIB_NAME_DOES_NOT_EXIST
-		case C.CASS_ERROR_LIB_UNABLE_TO_DETERMINE_PROTOCOL:
-			return CASS_ERROR_LIB_UNABLE_TO_DETERMINE_PROTOCOL
-		case C.CASS_ERROR_LIB_NULL_VALUE:
-			return CASS_ERROR_LIB_NULL_VALUE
-		case C.CASS_ERROR_LIB_NOT_IMPLEMENTED:
-			return CASS_ERROR_LIB_NOT_IMPLEMENTED
-		case C.CASS_ERROR_LIB_UNABLE_TO_CONNECT:
-			return CASS_ERROR_LIB_UNABLE_TO_CONNECT
-		case C.CASS_ERROR_LIB_UNABLE_TO_CLOSE:
-			return CASS_ERROR_LIB_UNABLE_TO_CLOSE
-		}
-	} else if source == CASS_ERROR_SOURCE_SERVER {
-		switch rc {
-		case C.CASS_OK:
-			return C.CASS_OK
-		case C.CASS_ERROR_SERVER_SERVER_ERROR:
-			return CASS_ERROR_SERVER_SERVER_ERROR
-		case C.CASS_ERROR_SERVER_PROTOCOL_ERROR:
-			return CASS_ERROR_SERVER_PROTOCOL_ERROR
-		case C.CASS_ERROR_SERVER_BAD_CREDENTIALS:
-			return CASS_ERROR_SERVER_BAD_CREDENTIALS
-		case C.CASS_ERROR_SERVER_UNAVAILABLE:
-			return CASS_ERROR_SERVER_UNAVAILABLE
-		case C.CASS_ERROR_SERVER_OVERLOADED:
-			return CASS_ERROR_SERVER_OVERLOADED
-		case C.CASS_ERROR_SERVER_IS_BOOTSTRAPPING:
-			return CASS_ERROR_SERVER_IS_BOOTSTRAPPING
-		case C.CASS_ERROR_SERVER_TRUNCATE_ERROR:
-			return CASS_ERROR_SERVER_TRUNCATE_ERROR
-		case C.CASS_ERROR_SERVER_WRITE_TIMEOUT:
-			return CASS_ERROR_SERVER_WRITE_TIMEOUT
-		case C.CASS_ERROR_SERVER_READ_TIMEOUT:
-			return CASS_ERROR_SERVER_READ_TIMEOUT
-		case C.CASS_ERROR_SERVER_SYNTAX_ERROR:
-			return CASS_ERROR_SERVER_SYNTAX_ERROR
-		case C.CASS_ERROR_SERVER_UNAUTHORIZED:
-			return CASS_ERROR_SERVER_UNAUTHORIZED
-		case C.CASS_ERROR_SERVER_INVALID_QUERY:
-			return CASS_ERROR_SERVER_INVALID_QUERY
-		case C.CASS_ERROR_SERVER_CONFIG_ERROR:
-			return CASS_ERROR_SERVER_CONFIG_ERROR
-		case C.CASS_ERROR_SERVER_ALREADY_EXISTS:
-			return CASS_ERROR_SERVER_ALREADY_EXISTS
-		case C.CASS_ERROR_SERVER_UNPREPARED:
-			return CASS_ERROR_SERVER_UNPREPARED
-		}
-	} else if source == CASS_ERROR_SOURCE_SSL {
-		switch rc {
-		case CASS_OK:
-			return C.CASS_OK
-		case C.CASS_ERROR_SSL_INVALID_CERT:
-			return CASS_ERROR_SSL_INVALID_CERT
-		case C.CASS_ERROR_SSL_INVALID_PRIVATE_KEY:
-			return CASS_ERROR_SSL_INVALID_PRIVATE_KEY
-		case C.CASS_ERROR_SSL_NO_PEER_CERT:
-			return CASS_ERROR_SSL_NO_PEER_CERT
-		case C.CASS_ERROR_SSL_INVALID_PEER_CERT:
-			return CASS_ERROR_SSL_INVALID_PEER_CERT
-		case C.CASS_ERROR_SSL_IDENTITY_MISMATCH:
-			return CASS_ERROR_SSL_IDENTITY_MISMATCH
-		}
+	if rc == C.CASS_OK {
+		return nil
 	}
-	return CASS_ERROR_LIB_UNEXPECTED_RESPONSE
+	return new_error_with_message(rc, future.ErrorMessage())
 }
 
 func (cluster *Cluster) SetContactPoints(contactPoints string) {
@@ -569,19 +657,57 @@ func (cluster *Cluster) SetMaxConnectionsPerHost(size uint) {
 func (cluster *Cluster) SessionConnect(session *Session) *Future {
 	future := new(Future)
 	future.cptr = C.cass_session_connect(session.cptr, cluster.cptr)
+	session.retry = cluster.retry
+	session.idempotent = cluster.idempotent
+	session.cache.size = cluster.cache_size
+	track(future)
 	return future
 }
 
+func (cluster *Cluster) SessionConnectKeyspace(session *Session, keyspace string) *Future {
+	cs := C.CString(keyspace)
+	defer C.free(unsafe.Pointer(cs))
+	future := new(Future)
+	future.cptr = C.cass_session_connect_keyspace(session.cptr, cluster.cptr, cs)
+	session.retry = cluster.retry
+	session.idempotent = cluster.idempotent
+	session.cache.size = cluster.cache_size
+	session.keyspace = keyspace
+	track(future)
+	return future
+}
+
+// Execute runs statement. If a Go RetryPolicy applies to it, the future
+// completes once the policy stops retrying, with the outcome of the last
+// attempt.
 func (session *Session) Execute(statement *Statement) *Future {
+	return session.execute_context(context.Background(), statement)
+}
+
+// execute_context is Execute with ctx bounding the retries of a Go
+// RetryPolicy.
+func (session *Session) execute_context(ctx context.Context, statement *Statement) *Future {
+	if policy := session.retry_policy(statement); policy != nil {
+		return session.execute_retry(ctx, statement, policy)
+	}
+	return session.execute(statement)
+}
+
+func (session *Session) execute(statement *Statement) *Future {
 	future := new(Future)
 	future.cptr = C.cass_session_execute(session.cptr, statement.cptr)
+	future.table = session.qualify(statement.table)
+	track(future)
 	return future
 }
 
 func (session *Session) Prepare(statement string) *Future {
 	cstring := C.CString(statement)
+	defer C.free(unsafe.Pointer(cstring))
 	future := new(Future)
 	future.cptr = C.cass_session_prepare(session.cptr, cstring)
+	future.table = select_table(statement)
+	track(future)
 	return future
 }
 
@@ -593,10 +719,12 @@ func (result *Result) ColumnCount() uint64 {
 	return uint64(C.cass_result_column_count(result.cptr))
 }
 
-// func (result *Result) ColumnName(index uint64) string {
-// 	column_name := C.cass_result_column_name(result.cptr, C.size_t(index))
-// 	return C.GoStringN(column_name.data, C.int(column_name.length))
-// }
+func (result *Result) ColumnName(index uint64) string {
+	var name *C.char
+	var length C.size_t
+	C.cass_result_column_name(result.cptr, C.size_t(index), &name, &length)
+	return C.GoStringN(name, C.int(length))
+}
 
 func (result *Result) ColumnType(index uint64) int {
 	return int(C.cass_result_column_type(result.cptr, C.size_t(index)))
@@ -613,82 +741,350 @@ func (result *Result) Next() bool {
 	return C.cass_iterator_next(result.iter) != 0
 }
 
+// on_row returns an error unless Next has moved result onto a row.
+func (result *Result) on_row() error {
+	if result == nil || result.iter == nil {
+		return new_error_with_message(C.CASS_ERROR_LIB_INVALID_STATE, "Next has not been called")
+	}
+	return nil
+}
+
 func (result *Result) Scan(args ...interface{}) error {
+	if err := result.on_row(); err != nil {
+		return err
+	}
 
 	if result.ColumnCount() != uint64(len(args)) {
-		errors.New("invalid argument count")
+		return new_error_with_message(C.CASS_ERROR_LIB_INVALID_ITEM_COUNT, "invalid argument count")
 	}
 
 	row := C.cass_iterator_get_row(result.iter)
 
-	var err C.CassError = C.CASS_OK
-
 	for i, v := range args {
 		value := C.cass_row_get_column(row, C.size_t(i))
+		if err := scan_value(value, v); err != nil {
+			return err
+		}
+	}
 
-		switch v := v.(type) {
-
-		case *string:
-			// var str unsafe.Pointer
-			// var length int64
-			// err = C.cass_value_get_string(value, &str, &length)
-			// if err != C.CASS_OK {
-			// 	return errors.New(C.GoString(C.cass_error_desc(err)))
-			// }
-			// *v = C.GoStringN(str, length)
-
-		case *[]byte:
-			// var b *[]byte
-			// var l int64
-			// err = C.cass_value_get_bytes(value, &b, &l)
-			// if err != C.CASS_OK {
-			// 	return errors.New(C.GoString(C.cass_error_desc(err)))
-			// }
-			// *v = C.GoBytes(unsafe.Pointer(b), l)
-
-		case *int32:
-			var i32 C.cass_int32_t
-			err = C.cass_value_get_int32(value, &i32)
-			if err != C.CASS_OK {
-				return errors.New(C.GoString(C.cass_error_desc(err)))
-			}
-			*v = int32(i32)
+	return nil
+}
 
-		case *int64:
-			var i64 C.cass_int64_t
-			err = C.cass_value_get_int64(value, &i64)
-			if err != C.CASS_OK {
-				return errors.New(C.GoString(C.cass_error_desc(err)))
-			}
-			*v = int64(i64)
+// is_null_of reports whether value is a NULL of one of types. A NULL of
+// any other type is left for the driver to report as a mismatch.
+func is_null_of(value *C.CassValue, types ...C.CassValueType) bool {
+	if C.cass_value_is_null(value) == 0 {
+		return false
+	}
+	vtype := C.cass_value_type(value)
+	for _, t := range types {
+		if vtype == t {
+			return true
+		}
+	}
+	return false
+}
 
-		case *float32:
-			var f32 C.cass_float_t
-			err = C.cass_value_get_float(value, &f32)
-			if err != C.CASS_OK {
-				return errors.New(C.GoString(C.cass_error_desc(err)))
-			}
-			*v = float32(f32)
+func scan_value(value *C.CassValue, v interface{}) error {
+	var err C.CassError = C.CASS_OK
 
-		case *float64:
-			var f64 C.cass_double_t
-			err = C.cass_value_get_double(value, &f64)
-			if err != C.CASS_OK {
-				return errors.New(C.GoString(C.cass_error_desc(err)))
-			}
-			*v = float64(f64)
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		// Nowhere to scan to, as with a nil *big.Int.
+		return type_mismatch(value, v)
+	}
+	if n, ok := v.(null_scanner); ok {
+		return n.scan_null(value)
+	}
+	if rv.Kind() == reflect.Ptr && rv.Type().Elem().Kind() == reflect.Ptr {
+		return scan_pointer(value, rv)
+	}
 
-		case *bool:
-			var b C.cass_bool_t
-			err = C.cass_value_get_bool(value, &b)
-			if err != C.CASS_OK {
-				return errors.New(C.GoString(C.cass_error_desc(err)))
-			}
-			*v = bool(b != 0)
+	switch v := v.(type) {
 
+	case *string:
+		switch C.cass_value_type(value) {
+		case C.CASS_VALUE_TYPE_ASCII, C.CASS_VALUE_TYPE_TEXT, C.CASS_VALUE_TYPE_VARCHAR:
 		default:
-			return errors.New("unsupported type in Scan: " + reflect.TypeOf(v).String())
+			return type_mismatch(value, v)
+		}
+		if C.cass_value_is_null(value) != 0 {
+			*v = ""
+			break
+		}
+		var str *C.char
+		var length C.size_t
+		err = C.cass_value_get_string(value, &str, &length)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+		*v = C.GoStringN(str, C.int(length))
+
+	case *[]byte:
+		switch C.cass_value_type(value) {
+		case C.CASS_VALUE_TYPE_BLOB, C.CASS_VALUE_TYPE_CUSTOM,
+			C.CASS_VALUE_TYPE_ASCII, C.CASS_VALUE_TYPE_TEXT, C.CASS_VALUE_TYPE_VARCHAR:
+		default:
+			return type_mismatch(value, v)
+		}
+		if C.cass_value_is_null(value) != 0 {
+			*v = nil
+			break
+		}
+		var b *C.cass_byte_t
+		var length C.size_t
+		err = C.cass_value_get_bytes(value, &b, &length)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+		*v = copy_bytes(*v, b, length)
+
+	case *int8:
+		if is_null_of(value, C.CASS_VALUE_TYPE_TINY_INT) {
+			*v = 0
+			break
+		}
+		var i8 C.cass_int8_t
+		err = C.cass_value_get_int8(value, &i8)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+		*v = int8(i8)
+
+	case *int16:
+		if is_null_of(value, C.CASS_VALUE_TYPE_SMALL_INT) {
+			*v = 0
+			break
+		}
+		var i16 C.cass_int16_t
+		err = C.cass_value_get_int16(value, &i16)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+		*v = int16(i16)
+
+	case *int32:
+		if is_null_of(value, C.CASS_VALUE_TYPE_INT) {
+			*v = 0
+			break
+		}
+		var i32 C.cass_int32_t
+		err = C.cass_value_get_int32(value, &i32)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+		*v = int32(i32)
+
+	case *int64:
+		if is_null_of(value, C.CASS_VALUE_TYPE_BIGINT, C.CASS_VALUE_TYPE_COUNTER, C.CASS_VALUE_TYPE_TIMESTAMP, C.CASS_VALUE_TYPE_TIME) {
+			*v = 0
+			break
+		}
+		var i64 C.cass_int64_t
+		err = C.cass_value_get_int64(value, &i64)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+		*v = int64(i64)
+
+	case *float32:
+		if is_null_of(value, C.CASS_VALUE_TYPE_FLOAT) {
+			*v = 0
+			break
+		}
+		var f32 C.cass_float_t
+		err = C.cass_value_get_float(value, &f32)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+		*v = float32(f32)
+
+	case *float64:
+		if is_null_of(value, C.CASS_VALUE_TYPE_DOUBLE) {
+			*v = 0
+			break
+		}
+		var f64 C.cass_double_t
+		err = C.cass_value_get_double(value, &f64)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+		*v = float64(f64)
+
+	case *bool:
+		if is_null_of(value, C.CASS_VALUE_TYPE_BOOLEAN) {
+			*v = false
+			break
+		}
+		var b C.cass_bool_t
+		err = C.cass_value_get_bool(value, &b)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+		*v = bool(b != 0)
+
+	case *Uuid:
+		switch C.cass_value_type(value) {
+		case C.CASS_VALUE_TYPE_UUID, C.CASS_VALUE_TYPE_TIMEUUID:
+		default:
+			return type_mismatch(value, v)
+		}
+		if C.cass_value_is_null(value) != 0 {
+			*v = Uuid{}
+			break
+		}
+		err = C.cass_value_get_uuid(value, &v.uuid)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+
+	case *[16]byte:
+		var uuid Uuid
+		if e := scan_value(value, &uuid); e != nil {
+			return e
+		}
+		*v = uuid.Bytes()
+
+	case *net.IP:
+		if C.cass_value_type(value) != C.CASS_VALUE_TYPE_INET {
+			return type_mismatch(value, v)
+		}
+		if C.cass_value_is_null(value) != 0 {
+			*v = nil
+			break
+		}
+		var inet C.CassInet
+		err = C.cass_value_get_inet(value, &inet)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+		*v = inet_ip(inet)
+
+	case *time.Time:
+		vtype := C.cass_value_type(value)
+		if vtype != C.CASS_VALUE_TYPE_TIMESTAMP && vtype != C.CASS_VALUE_TYPE_DATE {
+			return type_mismatch(value, v)
+		}
+		if C.cass_value_is_null(value) != 0 {
+			*v = time.Time{}
+			break
+		}
+		if vtype == C.CASS_VALUE_TYPE_DATE {
+			var date Date
+			if e := scan_value(value, &date); e != nil {
+				return e
+			}
+			*v = date.In(time.UTC)
+			break
+		}
+		var ms C.cass_int64_t
+		err = C.cass_value_get_int64(value, &ms)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+		*v = time.UnixMilli(int64(ms)).UTC()
+
+	case *Date:
+		if C.cass_value_type(value) != C.CASS_VALUE_TYPE_DATE {
+			return type_mismatch(value, v)
+		}
+		if C.cass_value_is_null(value) != 0 {
+			*v = Date{}
+			break
+		}
+		var days C.cass_uint32_t
+		err = C.cass_value_get_uint32(value, &days)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+		*v = new_date(days)
+
+	case *time.Duration:
+		if C.cass_value_type(value) != C.CASS_VALUE_TYPE_TIME {
+			return type_mismatch(value, v)
+		}
+		if C.cass_value_is_null(value) != 0 {
+			*v = 0
+			break
+		}
+		var nanos C.cass_int64_t
+		err = C.cass_value_get_int64(value, &nanos)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+		*v = time.Duration(nanos)
+
+	case *CqlDuration:
+		if C.cass_value_type(value) != C.CASS_VALUE_TYPE_DURATION {
+			return type_mismatch(value, v)
+		}
+		if C.cass_value_is_null(value) != 0 {
+			*v = CqlDuration{}
+			break
+		}
+		var months, days C.cass_int32_t
+		var nanos C.cass_int64_t
+		err = C.cass_value_get_duration(value, &months, &days, &nanos)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+		*v = CqlDuration{Months: int32(months), Days: int32(days), Nanos: int64(nanos)}
+
+	case *big.Int:
+		if C.cass_value_type(value) != C.CASS_VALUE_TYPE_VARINT {
+			return type_mismatch(value, v)
+		}
+		if C.cass_value_is_null(value) != 0 {
+			v.SetInt64(0)
+			break
+		}
+		var b *C.cass_byte_t
+		var length C.size_t
+		err = C.cass_value_get_bytes(value, &b, &length)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+		v.Set(varint_from_bytes(C.GoBytes(unsafe.Pointer(b), C.int(length))))
+
+	case *Decimal:
+		if C.cass_value_type(value) != C.CASS_VALUE_TYPE_DECIMAL {
+			return type_mismatch(value, v)
+		}
+		if C.cass_value_is_null(value) != 0 {
+			*v = Decimal{}
+			break
+		}
+		var b *C.cass_byte_t
+		var length C.size_t
+		var scale C.cass_int32_t
+		err = C.cass_value_get_decimal(value, &b, &length, &scale)
+		if err != C.CASS_OK {
+			return scan_error(err, value, v)
+		}
+		*v = Decimal{
+			Unscaled: varint_from_bytes(C.GoBytes(unsafe.Pointer(b), C.int(length))),
+			Scale:    int32(scale),
+		}
+
+	case *big.Float:
+		if C.cass_value_type(value) != C.CASS_VALUE_TYPE_DECIMAL {
+			return type_mismatch(value, v)
+		}
+		var decimal Decimal
+		if e := scan_value(value, &decimal); e != nil {
+			return e
+		}
+		r, e := decimal.Rat()
+		if e != nil {
+			return e
+		}
+		v.SetRat(r)
+
+	default:
+		if is_collection_target(v) {
+			return scan_collection(value, v)
 		}
+		return type_mismatch(value, v)
 	}
 
 	return nil
@@ -0,0 +1,147 @@
+package cassandra
+
+// #include <cassandra.h>
+import "C"
+import "io"
+import "runtime"
+
+// Every wrapper around a driver object implements io.Closer. Close is
+// idempotent, and a finalizer closes objects that become unreachable
+// without being closed. Finalizers are a safety net only: they run late, if
+// at all, so callers should still close what they create. Building with the
+// cassandra_debug tag records where each leaked object was allocated; see
+// Leaks.
+
+var (
+	_ io.Closer = (*Cluster)(nil)
+	_ io.Closer = (*Session)(nil)
+	_ io.Closer = (*Future)(nil)
+	_ io.Closer = (*Result)(nil)
+	_ io.Closer = (*Prepared)(nil)
+	_ io.Closer = (*Statement)(nil)
+	_ io.Closer = (*UuidGenerator)(nil)
+	_ io.Closer = (*Batch)(nil)
+	_ io.Closer = (*DriverRetryPolicy)(nil)
+)
+
+func track(obj io.Closer) {
+	record_allocation(obj)
+	runtime.SetFinalizer(obj, finalize)
+}
+
+func untrack(obj io.Closer) {
+	forget_allocation(obj)
+	runtime.SetFinalizer(obj, nil)
+}
+
+func finalize(obj io.Closer) {
+	record_leak(obj)
+	if session, ok := obj.(*Session); ok {
+		// Closing a session waits for its connections to shut down, which
+		// would hold up every other finalizer in the program.
+		go session.Close()
+		return
+	}
+	obj.Close()
+}
+
+func (cluster *Cluster) Close() error {
+	if cluster.cptr == nil {
+		return nil
+	}
+	C.cass_cluster_free(cluster.cptr)
+	cluster.cptr = nil
+	untrack(cluster)
+	return nil
+}
+
+// Close closes the session's connections, waiting for in-flight requests
+// to finish, and frees it. Concurrent calls wait for the first to finish.
+func (session *Session) Close() error {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.cptr == nil {
+		return nil
+	}
+	session.cache.close()
+
+	future := C.cass_session_close(session.cptr)
+	rc := C.cass_future_error_code(future)
+	var err error
+	if rc != C.CASS_OK && rc != C.CASS_ERROR_LIB_UNABLE_TO_CLOSE {
+		var message *C.char
+		var message_length C.size_t
+		C.cass_future_error_message(future, &message, &message_length)
+		err = new_error_with_message(rc, C.GoStringN(message, C.int(message_length)))
+	}
+	C.cass_future_free(future)
+
+	C.cass_session_free(session.cptr)
+	session.cptr = nil
+	untrack(session)
+	return err
+}
+
+// Close frees the future. A chained future closed before its last attempt
+// completes frees that attempt when it does.
+func (future *Future) Close() error {
+	future.mu.Lock()
+	defer future.mu.Unlock()
+	if future.closed {
+		return nil
+	}
+	future.closed = true
+	if future.cptr != nil {
+		C.cass_future_free(future.cptr)
+		future.cptr = nil
+	}
+	untrack(future)
+	return nil
+}
+
+func (result *Result) Close() error {
+	if result.iter != nil {
+		C.cass_iterator_free(result.iter)
+		result.iter = nil
+	}
+	if result.cptr == nil {
+		return nil
+	}
+	C.cass_result_free(result.cptr)
+	result.cptr = nil
+	untrack(result)
+	return nil
+}
+
+func (prepared *Prepared) Close() error {
+	if prepared.cptr == nil {
+		return nil
+	}
+	C.cass_prepared_free(prepared.cptr)
+	prepared.cptr = nil
+	untrack(prepared)
+	return nil
+}
+
+func (statement *Statement) Close() error {
+	statement.mu.Lock()
+	defer statement.mu.Unlock()
+	if statement.cptr == nil {
+		return nil
+	}
+	C.cass_statement_free(statement.cptr)
+	statement.cptr = nil
+	statement.prepared = nil
+	untrack(statement)
+	return nil
+}
+
+func (generator *UuidGenerator) Close() error {
+	if generator.cptr == nil {
+		return nil
+	}
+	C.cass_uuid_gen_free(generator.cptr)
+	generator.cptr = nil
+	untrack(generator)
+	return nil
+}
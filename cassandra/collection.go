@@ -0,0 +1,352 @@
+package cassandra
+
+// #include <stdlib.h>
+// #include <cassandra.h>
+import "C"
+import "unsafe"
+import "reflect"
+
+// Go slices bind to CQL lists (or sets, when a prepared statement says so),
+// maps with struct{} values bind to sets and all other maps bind to maps.
+// Scanning goes the other way and accepts any combination of the three,
+// nested to any depth.
+
+var empty_struct = reflect.TypeOf(struct{}{})
+
+func is_set_type(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Elem() == empty_struct
+}
+
+func is_collection_value(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+func is_collection_target(v interface{}) bool {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return false
+	}
+	switch t.Elem().Kind() {
+	case reflect.Slice, reflect.Map:
+		return true
+	}
+	return false
+}
+
+func is_collection_type(vtype C.CassValueType) bool {
+	switch vtype {
+	case C.CASS_VALUE_TYPE_LIST, C.CASS_VALUE_TYPE_SET, C.CASS_VALUE_TYPE_MAP:
+		return true
+	}
+	return false
+}
+
+func bind_collection(statement *Statement, index int, v interface{}) error {
+	var data_type *C.CassDataType
+	if statement.prepared != nil && statement.prepared.cptr != nil {
+		data_type = C.cass_prepared_parameter_data_type(statement.prepared.cptr, C.size_t(index))
+	}
+
+	collection, err := new_collection(reflect.ValueOf(v), data_type)
+	if err != nil {
+		return err
+	}
+	defer C.cass_collection_free(collection)
+
+	rc := C.cass_statement_bind_collection(statement.cptr, C.size_t(index), collection)
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+func bind_collection_by_name(statement *Statement, name *C.char, v interface{}) error {
+	var data_type *C.CassDataType
+	if statement.prepared != nil && statement.prepared.cptr != nil {
+		data_type = C.cass_prepared_parameter_data_type_by_name(statement.prepared.cptr, name)
+	}
+
+	collection, err := new_collection(reflect.ValueOf(v), data_type)
+	if err != nil {
+		return err
+	}
+	defer C.cass_collection_free(collection)
+
+	rc := C.cass_statement_bind_collection_by_name(statement.cptr, name, collection)
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+func new_collection(v reflect.Value, data_type *C.CassDataType) (*C.CassCollection, error) {
+	var ctype C.CassCollectionType
+	count := v.Len()
+
+	switch {
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		ctype = C.CASS_COLLECTION_TYPE_LIST
+	case is_set_type(v.Type()):
+		ctype = C.CASS_COLLECTION_TYPE_SET
+	case v.Kind() == reflect.Map:
+		ctype = C.CASS_COLLECTION_TYPE_MAP
+		count *= 2
+	default:
+		return nil, new_error_with_message(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE, "unsupported type in Bind: "+v.Type().String())
+	}
+
+	var collection *C.CassCollection
+	if data_type != nil && is_collection_type(C.cass_data_type_type(data_type)) {
+		ctype = C.CassCollectionType(C.cass_data_type_type(data_type))
+		collection = C.cass_collection_new_from_data_type(data_type, C.size_t(count))
+	} else {
+		data_type = nil
+		collection = C.cass_collection_new(ctype, C.size_t(count))
+	}
+
+	err := fill_collection(collection, ctype, v, data_type)
+	if err != nil {
+		C.cass_collection_free(collection)
+		return nil, err
+	}
+	return collection, nil
+}
+
+func fill_collection(collection *C.CassCollection, ctype C.CassCollectionType, v reflect.Value, data_type *C.CassDataType) error {
+	switch {
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && ctype != C.CASS_COLLECTION_TYPE_MAP:
+		elem_type := sub_data_type(data_type, 0)
+		for i := 0; i < v.Len(); i++ {
+			if err := append_value(collection, v.Index(i), elem_type); err != nil {
+				return err
+			}
+		}
+
+	case is_set_type(v.Type()) && ctype == C.CASS_COLLECTION_TYPE_SET:
+		elem_type := sub_data_type(data_type, 0)
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := append_value(collection, iter.Key(), elem_type); err != nil {
+				return err
+			}
+		}
+
+	case v.Kind() == reflect.Map && ctype == C.CASS_COLLECTION_TYPE_MAP:
+		key_type := sub_data_type(data_type, 0)
+		value_type := sub_data_type(data_type, 1)
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := append_value(collection, iter.Key(), key_type); err != nil {
+				return err
+			}
+			if err := append_value(collection, iter.Value(), value_type); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return new_error_with_message(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE, "invalid collection type in Bind: "+v.Type().String())
+	}
+
+	return nil
+}
+
+func sub_data_type(data_type *C.CassDataType, index int) *C.CassDataType {
+	if data_type == nil || C.cass_data_type_sub_type_count(data_type) <= C.size_t(index) {
+		return nil
+	}
+	return C.cass_data_type_sub_data_type(data_type, C.size_t(index))
+}
+
+func append_value(collection *C.CassCollection, v reflect.Value, data_type *C.CassDataType) error {
+	return bind_to(collection_binder{collection, data_type}, v.Interface())
+}
+
+// collection_binder appends to collection. data_type is the type of the
+// elements, if known.
+type collection_binder struct {
+	collection *C.CassCollection
+	data_type  *C.CassDataType
+}
+
+func (b collection_binder) bind_null() error {
+	return new_error_with_message(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE, "collections cannot hold null or unset values")
+}
+
+func (b collection_binder) bind_unset() error {
+	return b.bind_null()
+}
+
+func (b collection_binder) bind_int8(v C.cass_int8_t) C.CassError {
+	return C.cass_collection_append_int8(b.collection, v)
+}
+
+func (b collection_binder) bind_int16(v C.cass_int16_t) C.CassError {
+	return C.cass_collection_append_int16(b.collection, v)
+}
+
+func (b collection_binder) bind_int32(v C.cass_int32_t) C.CassError {
+	return C.cass_collection_append_int32(b.collection, v)
+}
+
+func (b collection_binder) bind_int64(v C.cass_int64_t) C.CassError {
+	return C.cass_collection_append_int64(b.collection, v)
+}
+
+func (b collection_binder) bind_uint32(v C.cass_uint32_t) C.CassError {
+	return C.cass_collection_append_uint32(b.collection, v)
+}
+
+func (b collection_binder) bind_float(v C.cass_float_t) C.CassError {
+	return C.cass_collection_append_float(b.collection, v)
+}
+
+func (b collection_binder) bind_double(v C.cass_double_t) C.CassError {
+	return C.cass_collection_append_double(b.collection, v)
+}
+
+func (b collection_binder) bind_bool(v C.cass_bool_t) C.CassError {
+	return C.cass_collection_append_bool(b.collection, v)
+}
+
+func (b collection_binder) bind_string(v string) C.CassError {
+	cs := C.CString(v)
+	defer C.free(unsafe.Pointer(cs))
+	return C.cass_collection_append_string_n(b.collection, cs, C.size_t(len(v)))
+}
+
+func (b collection_binder) bind_bytes(v []byte) C.CassError {
+	return C.cass_collection_append_bytes(b.collection, bytes_ptr(v), C.size_t(len(v)))
+}
+
+func (b collection_binder) bind_uuid(v C.struct_CassUuid_) C.CassError {
+	return C.cass_collection_append_uuid(b.collection, v)
+}
+
+func (b collection_binder) bind_inet(v C.CassInet) C.CassError {
+	return C.cass_collection_append_inet(b.collection, v)
+}
+
+func (b collection_binder) bind_decimal(varint []byte, scale C.cass_int32_t) C.CassError {
+	return C.cass_collection_append_decimal(b.collection, bytes_ptr(varint), C.size_t(len(varint)), scale)
+}
+
+func (b collection_binder) bind_duration(months, days C.cass_int32_t, nanos C.cass_int64_t) C.CassError {
+	return C.cass_collection_append_duration(b.collection, months, days, nanos)
+}
+
+func (b collection_binder) bind_collection(v interface{}) error {
+	nested, err := new_collection(reflect.ValueOf(v), b.data_type)
+	if err != nil {
+		return err
+	}
+	defer C.cass_collection_free(nested)
+
+	if rc := C.cass_collection_append_collection(b.collection, nested); rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+func bytes_ptr(b []byte) *C.cass_byte_t {
+	if len(b) == 0 {
+		return nil
+	}
+	return (*C.cass_byte_t)(unsafe.Pointer(&b[0]))
+}
+
+// element_compatible reports whether values of the CQL type vtype can be
+// scanned into a Go value of type t, as far as collection nesting goes.
+// Scalar mismatches are left for scan_value to report.
+func element_compatible(vtype C.CassValueType, t reflect.Type) bool {
+	if t.Kind() == reflect.Interface {
+		return false
+	}
+	if is_collection_type(vtype) {
+		return t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 || t.Kind() == reflect.Map
+	}
+	return t.Kind() != reflect.Map && (t.Kind() != reflect.Slice || t.Elem().Kind() == reflect.Uint8)
+}
+
+func scan_collection(value *C.CassValue, v interface{}) error {
+	target := reflect.ValueOf(v).Elem()
+	t := target.Type()
+
+	if C.cass_value_is_null(value) != 0 {
+		target.Set(reflect.Zero(t))
+		return nil
+	}
+
+	switch C.cass_value_type(value) {
+
+	case C.CASS_VALUE_TYPE_LIST, C.CASS_VALUE_TYPE_SET:
+		primary := C.cass_value_primary_sub_type(value)
+		iter := C.cass_iterator_from_collection(value)
+		defer C.cass_iterator_free(iter)
+
+		switch {
+		case t.Kind() == reflect.Slice:
+			if !element_compatible(primary, t.Elem()) {
+				break
+			}
+			out := reflect.MakeSlice(t, 0, int(C.cass_value_item_count(value)))
+			for C.cass_iterator_next(iter) != 0 {
+				elem := reflect.New(t.Elem())
+				if err := scan_value(C.cass_iterator_get_value(iter), elem.Interface()); err != nil {
+					return err
+				}
+				out = reflect.Append(out, elem.Elem())
+			}
+			target.Set(out)
+			return nil
+
+		case is_set_type(t):
+			if !element_compatible(primary, t.Key()) {
+				break
+			}
+			out := reflect.MakeMapWithSize(t, int(C.cass_value_item_count(value)))
+			for C.cass_iterator_next(iter) != 0 {
+				key := reflect.New(t.Key())
+				if err := scan_value(C.cass_iterator_get_value(iter), key.Interface()); err != nil {
+					return err
+				}
+				out.SetMapIndex(key.Elem(), reflect.Zero(t.Elem()))
+			}
+			target.Set(out)
+			return nil
+		}
+
+	case C.CASS_VALUE_TYPE_MAP:
+		if t.Kind() != reflect.Map ||
+			!element_compatible(C.cass_value_primary_sub_type(value), t.Key()) ||
+			!element_compatible(C.cass_value_secondary_sub_type(value), t.Elem()) {
+			break
+		}
+		iter := C.cass_iterator_from_map(value)
+		defer C.cass_iterator_free(iter)
+
+		out := reflect.MakeMapWithSize(t, int(C.cass_value_item_count(value)))
+		for C.cass_iterator_next(iter) != 0 {
+			key := reflect.New(t.Key())
+			if err := scan_value(C.cass_iterator_get_map_key(iter), key.Interface()); err != nil {
+				return err
+			}
+			elem := reflect.New(t.Elem())
+			if err := scan_value(C.cass_iterator_get_map_value(iter), elem.Interface()); err != nil {
+				return err
+			}
+			out.SetMapIndex(key.Elem(), elem.Elem())
+		}
+		target.Set(out)
+		return nil
+	}
+
+	return type_mismatch(value, v)
+}
@@ -0,0 +1,49 @@
+package cassandra
+
+// #include <cassandra.h>
+import "C"
+import "strings"
+
+type Consistency int
+
+const (
+	CASS_CONSISTENCY_ANY          Consistency = 0x0000
+	CASS_CONSISTENCY_ONE          Consistency = 0x0001
+	CASS_CONSISTENCY_TWO          Consistency = 0x0002
+	CASS_CONSISTENCY_THREE        Consistency = 0x0003
+	CASS_CONSISTENCY_QUORUM       Consistency = 0x0004
+	CASS_CONSISTENCY_ALL          Consistency = 0x0005
+	CASS_CONSISTENCY_LOCAL_QUORUM Consistency = 0x0006
+	CASS_CONSISTENCY_EACH_QUORUM  Consistency = 0x0007
+	CASS_CONSISTENCY_SERIAL       Consistency = 0x0008
+	CASS_CONSISTENCY_LOCAL_SERIAL Consistency = 0x0009
+	CASS_CONSISTENCY_LOCAL_ONE    Consistency = 0x000A
+	CASS_CONSISTENCY_UNKNOWN      Consistency = 0xFFFF
+)
+
+func (consistency Consistency) String() string {
+	return C.GoString(C.cass_consistency_string(C.CassConsistency(consistency)))
+}
+
+var consistency_names = map[string]Consistency{
+	"any":          CASS_CONSISTENCY_ANY,
+	"one":          CASS_CONSISTENCY_ONE,
+	"two":          CASS_CONSISTENCY_TWO,
+	"three":        CASS_CONSISTENCY_THREE,
+	"quorum":       CASS_CONSISTENCY_QUORUM,
+	"all":          CASS_CONSISTENCY_ALL,
+	"local_quorum": CASS_CONSISTENCY_LOCAL_QUORUM,
+	"each_quorum":  CASS_CONSISTENCY_EACH_QUORUM,
+	"serial":       CASS_CONSISTENCY_SERIAL,
+	"local_serial": CASS_CONSISTENCY_LOCAL_SERIAL,
+	"local_one":    CASS_CONSISTENCY_LOCAL_ONE,
+}
+
+// ParseConsistency parses a consistency level by its CQL name, such as
+// "LOCAL_QUORUM". Case is ignored.
+func ParseConsistency(name string) (Consistency, error) {
+	if consistency, ok := consistency_names[strings.ToLower(name)]; ok {
+		return consistency, nil
+	}
+	return CASS_CONSISTENCY_UNKNOWN, bad_params("unknown consistency: " + name)
+}
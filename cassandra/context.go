@@ -0,0 +1,146 @@
+package cassandra
+
+// #include <cassandra.h>
+import "C"
+import "context"
+import "time"
+
+// ExecuteContext executes statement and waits for its result, returning
+// early with ctx.Err() if ctx is cancelled or its deadline passes first. A
+// deadline on ctx is also applied to statement as its request timeout, so
+// the driver gives up on the request at the same time. The statement's own
+// timeout is put back once the request is over.
+func (session *Session) ExecuteContext(ctx context.Context, statement *Statement) (*Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	restore, err := apply_deadline(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+
+	future := session.execute_context(ctx, statement)
+	if err := await_then(ctx, future, restore); err != nil {
+		return nil, err
+	}
+	defer future.Close()
+	return future.Result(), nil
+}
+
+// PrepareContext prepares query and waits for the prepared statement,
+// returning early with ctx.Err() if ctx is done first.
+func (session *Session) PrepareContext(ctx context.Context, query string) (*Prepared, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	future := session.Prepare(query)
+	if err := await(ctx, future); err != nil {
+		return nil, err
+	}
+	defer future.Close()
+	return future.Prepared(), nil
+}
+
+// ConnectContext connects session to the cluster and waits for the
+// connection, returning early with ctx.Err() if ctx is done first. The
+// driver keeps connecting in the background in that case; close the
+// session to stop it.
+func (cluster *Cluster) ConnectContext(ctx context.Context, session *Session) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	future := cluster.SessionConnect(session)
+	if err := await(ctx, future); err != nil {
+		return err
+	}
+	future.Close()
+	return nil
+}
+
+// ConnectKeyspaceContext is ConnectContext with keyspace as the session's
+// initial keyspace.
+func (cluster *Cluster) ConnectKeyspaceContext(ctx context.Context, session *Session, keyspace string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	future := cluster.SessionConnectKeyspace(session, keyspace)
+	if err := await(ctx, future); err != nil {
+		return err
+	}
+	future.Close()
+	return nil
+}
+
+// await waits for future or ctx, whichever is done first. If the future
+// fails it is freed and its error returned. If ctx wins, the future is left
+// to finish in the background and freed once it does.
+func await(ctx context.Context, future *Future) error {
+	return await_then(ctx, future, func() {})
+}
+
+// await_then is await that also calls then once future completes: before
+// returning if the future wins, and from the future's completion callback
+// if ctx does.
+func await_then(ctx context.Context, future *Future, then func()) error {
+	select {
+	case <-future.Done():
+		then()
+		if err := future.Err(); err != nil {
+			future.Close()
+			return err
+		}
+		return nil
+
+	case <-ctx.Done():
+		future.OnComplete(func(future *Future) {
+			then()
+			future.Close()
+		})
+		return ctx.Err()
+	}
+}
+
+// apply_deadline shortens statement's request timeout to ctx's deadline if
+// that comes first. It returns the function that puts the statement's own
+// timeout back, to be called once the request is over.
+func apply_deadline(ctx context.Context, statement *Statement) (func(), error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return func() {}, nil
+	}
+	timeout := time.Until(deadline)
+	if timeout <= 0 {
+		return nil, context.DeadlineExceeded
+	}
+	if statement.timeout != 0 && statement.timeout <= timeout {
+		return func() {}, nil
+	}
+	C.cass_statement_set_request_timeout(statement.cptr, C.cass_uint64_t(timeout_ms(timeout)))
+	return statement.restore_timeout, nil
+}
+
+// restore_timeout puts back the timeout set with SetRequestTimeout, or the
+// cluster's if there is none. It may run after the statement is closed.
+func (statement *Statement) restore_timeout() {
+	statement.mu.Lock()
+	defer statement.mu.Unlock()
+	if statement.cptr == nil {
+		return
+	}
+	ms := C.cass_uint64_t(C.CASS_UINT64_MAX)
+	if statement.timeout != 0 {
+		ms = C.cass_uint64_t(timeout_ms(statement.timeout))
+	}
+	C.cass_statement_set_request_timeout(statement.cptr, ms)
+}
+
+func timeout_ms(timeout time.Duration) uint64 {
+	ms := uint64((timeout + time.Millisecond - 1) / time.Millisecond)
+	if ms == 0 {
+		ms = 1
+	}
+	return ms
+}
@@ -0,0 +1,371 @@
+package cassandra
+
+// #include <cassandra.h>
+import "C"
+import "fmt"
+import "math/big"
+import "net"
+import "reflect"
+import "strings"
+import "time"
+
+// DataType is the full CQL type of a column or value.
+type DataType struct {
+	// Type is one of the CASS_VALUE_TYPE_* constants.
+	Type int
+
+	// Name is the type name of a user-defined type, or the class name of
+	// a custom type. Keyspace is set for user-defined types.
+	Name     string
+	Keyspace string
+
+	Frozen bool
+
+	// SubTypes holds the element type of a list or set, the key and value
+	// types of a map, the members of a tuple or the field types of a
+	// user-defined type. FieldNames holds the matching field names of a
+	// user-defined type.
+	SubTypes   []DataType
+	FieldNames []string
+}
+
+func new_data_type(cdt *C.CassDataType) DataType {
+	if cdt == nil {
+		return DataType{Type: CASS_VALUE_TYPE_UNKNOWN}
+	}
+
+	dt := DataType{
+		Type:   int(C.cass_data_type_type(cdt)),
+		Frozen: C.cass_data_type_is_frozen(cdt) != 0,
+	}
+
+	var str *C.char
+	var length C.size_t
+	switch dt.Type {
+	case CASS_VALUE_TYPE_UDT:
+		if C.cass_data_type_type_name(cdt, &str, &length) == C.CASS_OK {
+			dt.Name = C.GoStringN(str, C.int(length))
+		}
+		if C.cass_data_type_keyspace(cdt, &str, &length) == C.CASS_OK {
+			dt.Keyspace = C.GoStringN(str, C.int(length))
+		}
+	case CASS_VALUE_TYPE_CUSTOM:
+		if C.cass_data_type_class_name(cdt, &str, &length) == C.CASS_OK {
+			dt.Name = C.GoStringN(str, C.int(length))
+		}
+	}
+
+	count := int(C.cass_data_type_sub_type_count(cdt))
+	for i := 0; i < count; i++ {
+		dt.SubTypes = append(dt.SubTypes, new_data_type(C.cass_data_type_sub_data_type(cdt, C.size_t(i))))
+		if dt.Type == CASS_VALUE_TYPE_UDT {
+			var name string
+			if C.cass_data_type_sub_type_name(cdt, C.size_t(i), &str, &length) == C.CASS_OK {
+				name = C.GoStringN(str, C.int(length))
+			}
+			dt.FieldNames = append(dt.FieldNames, name)
+		}
+	}
+
+	return dt
+}
+
+// String returns the type as CQL would spell it, such as
+// "map<text, frozen<list<int>>>".
+func (dt DataType) String() string {
+	var s string
+	switch dt.Type {
+	case CASS_VALUE_TYPE_LIST, CASS_VALUE_TYPE_SET, CASS_VALUE_TYPE_MAP, CASS_VALUE_TYPE_TUPLE:
+		members := make([]string, len(dt.SubTypes))
+		for i, sub := range dt.SubTypes {
+			members[i] = sub.String()
+		}
+		s = ValueTypeName(dt.Type) + "<" + strings.Join(members, ", ") + ">"
+	case CASS_VALUE_TYPE_UDT:
+		s = dt.Name
+		if dt.Keyspace != "" {
+			s = dt.Keyspace + "." + s
+		}
+	case CASS_VALUE_TYPE_CUSTOM:
+		s = "'" + dt.Name + "'"
+	default:
+		s = ValueTypeName(dt.Type)
+	}
+
+	if dt.Frozen {
+		s = "frozen<" + s + ">"
+	}
+	return s
+}
+
+// Column describes one column of a result.
+type Column struct {
+	Keyspace string
+	Table    string
+	Name     string
+	Type     DataType
+}
+
+// Columns describes the columns of the result, in order.
+//
+// The C driver does not report the keyspace and table of a result column,
+// so they are taken from the query instead. They are set for a SELECT from
+// a single table, with the keyspace the session connected to standing in
+// for an unqualified table name, and are empty for other statements and
+// for results that were not returned by Session.Execute.
+func (result *Result) Columns() []Column {
+	count := result.ColumnCount()
+	columns := make([]Column, count)
+	for i := uint64(0); i < count; i++ {
+		columns[i] = Column{
+			Keyspace: result.table.keyspace,
+			Table:    result.table.table,
+			Name:     result.ColumnName(i),
+			Type:     result.ColumnDataType(i),
+		}
+	}
+	return columns
+}
+
+// table_name is a table a query reads from.
+type table_name struct {
+	keyspace string
+	table    string
+}
+
+// select_table returns the table a SELECT statement reads from, or the
+// zero table_name if query is not a SELECT.
+func select_table(query string) table_name {
+	tokens := cql_tokens(query)
+	if len(tokens) == 0 || !strings.EqualFold(tokens[0], "select") {
+		return table_name{}
+	}
+
+	depth := 0
+	for i, token := range tokens {
+		switch {
+		case token == "(":
+			depth++
+		case token == ")":
+			depth--
+		case depth == 0 && strings.EqualFold(token, "from"):
+			return from_table(tokens[i+1:])
+		}
+	}
+	return table_name{}
+}
+
+// from_table reads a table name, which may be qualified by a keyspace,
+// from the tokens that follow FROM.
+func from_table(tokens []string) table_name {
+	if len(tokens) == 0 || !is_name_token(tokens[0]) {
+		return table_name{}
+	}
+	if len(tokens) >= 3 && tokens[1] == "." && is_name_token(tokens[2]) {
+		return table_name{keyspace: marker_name(tokens[0]), table: marker_name(tokens[2])}
+	}
+	return table_name{table: marker_name(tokens[0])}
+}
+
+// qualify fills in the session's keyspace for a table named without one.
+func (session *Session) qualify(table table_name) table_name {
+	if table.table != "" && table.keyspace == "" {
+		table.keyspace = session.keyspace
+	}
+	return table
+}
+
+func (result *Result) ColumnDataType(index uint64) DataType {
+	return new_data_type(C.cass_result_column_data_type(result.cptr, C.size_t(index)))
+}
+
+// MapScan copies the current row into m, keyed by column name. Values have
+// the Go type Scan would use for the column: int8 for tinyint, int16 for
+// smallint, int32 for int, string for text, Uuid for uuid, net.IP for
+// inet, time.Time for timestamp, Date for date, time.Duration for time,
+// CqlDuration for duration, *big.Int for varint, Decimal for decimal,
+// []interface{} for lists, sets and tuples, map[interface{}]interface{}
+// for maps and map[string]interface{} for user-defined types. NULL columns
+// are stored as nil. Map keys that cannot be Go map keys, such as frozen
+// collections and user-defined types, or that would compare by pointer,
+// such as varints, are stored as strings.
+func (result *Result) MapScan(m map[string]interface{}) error {
+	if err := result.on_row(); err != nil {
+		return err
+	}
+	row := C.cass_iterator_get_row(result.iter)
+	count := result.ColumnCount()
+	for i := uint64(0); i < count; i++ {
+		v, err := generic_value(C.cass_row_get_column(row, C.size_t(i)))
+		if err != nil {
+			return err
+		}
+		m[result.ColumnName(i)] = v
+	}
+	return nil
+}
+
+func generic_value(value *C.CassValue) (interface{}, error) {
+	if value == nil || C.cass_value_is_null(value) != 0 {
+		return nil, nil
+	}
+
+	switch C.cass_value_type(value) {
+	case C.CASS_VALUE_TYPE_TINY_INT:
+		var v int8
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_SMALL_INT:
+		var v int16
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_INT:
+		var v int32
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_BIGINT, C.CASS_VALUE_TYPE_COUNTER:
+		var v int64
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_FLOAT:
+		var v float32
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_DOUBLE:
+		var v float64
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_BOOLEAN:
+		var v bool
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_ASCII, C.CASS_VALUE_TYPE_TEXT, C.CASS_VALUE_TYPE_VARCHAR:
+		var v string
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_BLOB, C.CASS_VALUE_TYPE_CUSTOM:
+		var v []byte
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_TIMESTAMP:
+		var v time.Time
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_DATE:
+		var v Date
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_TIME:
+		var v time.Duration
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_DURATION:
+		var v CqlDuration
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_VARINT:
+		v := new(big.Int)
+		err := scan_value(value, v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_DECIMAL:
+		var v Decimal
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_UUID, C.CASS_VALUE_TYPE_TIMEUUID:
+		var v Uuid
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_INET:
+		var v net.IP
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_LIST, C.CASS_VALUE_TYPE_SET:
+		return generic_items(C.cass_iterator_from_collection(value), int(C.cass_value_item_count(value)))
+
+	case C.CASS_VALUE_TYPE_TUPLE:
+		return generic_items(C.cass_iterator_from_tuple(value), int(C.cass_value_item_count(value)))
+
+	case C.CASS_VALUE_TYPE_MAP:
+		iter := C.cass_iterator_from_map(value)
+		defer C.cass_iterator_free(iter)
+		out := make(map[interface{}]interface{}, int(C.cass_value_item_count(value)))
+		for C.cass_iterator_next(iter) != 0 {
+			key, err := generic_value(C.cass_iterator_get_map_key(iter))
+			if err != nil {
+				return nil, err
+			}
+			elem, err := generic_value(C.cass_iterator_get_map_value(iter))
+			if err != nil {
+				return nil, err
+			}
+			out[map_key(key)] = elem
+		}
+		return out, nil
+
+	case C.CASS_VALUE_TYPE_UDT:
+		iter := C.cass_iterator_fields_from_user_type(value)
+		defer C.cass_iterator_free(iter)
+		out := make(map[string]interface{})
+		for C.cass_iterator_next(iter) != 0 {
+			var name *C.char
+			var length C.size_t
+			if rc := C.cass_iterator_get_user_type_field_name(iter, &name, &length); rc != C.CASS_OK {
+				return nil, new_error(rc)
+			}
+			field, err := generic_value(C.cass_iterator_get_user_type_field_value(iter))
+			if err != nil {
+				return nil, err
+			}
+			out[C.GoStringN(name, C.int(length))] = field
+		}
+		return out, nil
+	}
+
+	return nil, new_error_with_message(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE,
+		"unsupported column type in MapScan: "+ValueTypeName(int(C.cass_value_type(value))))
+}
+
+// map_key converts a decoded map key to one that Go can hash and compare
+// by value.
+func map_key(key interface{}) interface{} {
+	switch k := key.(type) {
+	case []byte:
+		return string(k)
+	case net.IP, *big.Int, Decimal:
+		return fmt.Sprint(k)
+	}
+	if key != nil && !reflect.TypeOf(key).Comparable() {
+		return fmt.Sprint(key)
+	}
+	return key
+}
+
+func generic_items(iter *C.CassIterator, count int) (interface{}, error) {
+	defer C.cass_iterator_free(iter)
+	out := make([]interface{}, 0, count)
+	for C.cass_iterator_next(iter) != 0 {
+		item, err := generic_value(C.cass_iterator_get_value(iter))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
@@ -0,0 +1,57 @@
+package cassandra
+
+import "math/big"
+import "net"
+import "testing"
+
+func TestMapKey(t *testing.T) {
+	tests := []struct {
+		key  interface{}
+		want interface{}
+	}{
+		{int32(7), int32(7)},
+		{"text", "text"},
+		{[]byte("blob"), "blob"},
+		{net.IPv4(10, 0, 0, 1), "10.0.0.1"},
+		{big.NewInt(-129), "-129"},
+		{Decimal{Unscaled: big.NewInt(1250), Scale: 2}, "12.50"},
+		{[]interface{}{int32(1), "a"}, "[1 a]"},
+		{map[string]interface{}{"x": int32(1)}, "map[x:1]"},
+		{map[interface{}]interface{}{"k": "v"}, "map[k:v]"},
+	}
+
+	for _, test := range tests {
+		got := map_key(test.key)
+		if got != test.want {
+			t.Errorf("map_key(%#v) = %#v, want %#v", test.key, got, test.want)
+		}
+		// Every key must be usable in the map MapScan builds.
+		m := map[interface{}]interface{}{}
+		m[got] = true
+	}
+}
+
+func TestSelectTable(t *testing.T) {
+	tests := []struct {
+		query string
+		want  table_name
+	}{
+		{"SELECT * FROM users", table_name{table: "users"}},
+		{"select id from Shop.Users where id = ?", table_name{keyspace: "shop", table: "users"}},
+		{`SELECT "from" FROM "Shop"."My ""Users"""`, table_name{keyspace: "Shop", table: `My "Users"`}},
+		{"SELECT count(*) FROM ks.t", table_name{keyspace: "ks", table: "t"}},
+		{"SELECT token(id) -- FROM comment\nFROM /* FROM x */ ks . t", table_name{keyspace: "ks", table: "t"}},
+		{"SELECT 'FROM x' FROM t", table_name{table: "t"}},
+		{"  SELECT $$FROM x$$ FROM t", table_name{table: "t"}},
+		{"INSERT INTO t (id) VALUES (?)", table_name{}},
+		{"UPDATE t SET a = 1", table_name{}},
+		{"SELECT * FROM", table_name{}},
+		{"", table_name{}},
+	}
+
+	for _, test := range tests {
+		if got := select_table(test.query); got != test.want {
+			t.Errorf("select_table(%q) = %+v, want %+v", test.query, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package cassandra
+
+// #include <cassandra.h>
+import "C"
+import "encoding"
+import "math/big"
+import "strconv"
+import "strings"
+
+// varint columns map to *big.Int and decimal columns to Decimal or
+// *big.Float. On the wire a varint is a two's-complement big-endian integer
+// in the fewest bytes that hold it, and a decimal is a varint together
+// with a 32 bit scale.
+
+var (
+	_ encoding.TextMarshaler   = Decimal{}
+	_ encoding.TextUnmarshaler = (*Decimal)(nil)
+)
+
+// Decimal is the value Unscaled × 10^-Scale, as stored in a decimal column.
+// A nil Unscaled is zero.
+type Decimal struct {
+	Unscaled *big.Int
+	Scale    int32
+}
+
+func NewDecimal(unscaled *big.Int, scale int32) Decimal {
+	return Decimal{Unscaled: unscaled, Scale: scale}
+}
+
+// ParseDecimal parses a decimal number such as "-12.50" or "1.5e-3",
+// keeping every digit given, trailing zeros included.
+func ParseDecimal(s string) (Decimal, error) {
+	invalid := bad_params("invalid decimal: " + s)
+
+	mantissa, exponent := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 64)
+		if err != nil {
+			return Decimal{}, invalid
+		}
+		mantissa, exponent = s[:i], e
+	}
+
+	digits := mantissa
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		digits = mantissa[:i] + mantissa[i+1:]
+		exponent -= int64(len(mantissa) - i - 1)
+	}
+	unsigned := strings.TrimLeft(digits, "+-")
+	if unsigned == "" || len(digits)-len(unsigned) > 1 || strings.Trim(unsigned, "0123456789") != "" {
+		return Decimal{}, invalid
+	}
+
+	unscaled, ok := new(big.Int).SetString(digits, 10)
+	if !ok || -exponent < -1<<31 || -exponent > 1<<31-1 {
+		return Decimal{}, invalid
+	}
+	return Decimal{Unscaled: unscaled, Scale: int32(-exponent)}, nil
+}
+
+func (decimal Decimal) unscaled() *big.Int {
+	if decimal.Unscaled == nil {
+		return new(big.Int)
+	}
+	return decimal.Unscaled
+}
+
+// max_decimal_scale bounds the scales Rat and Float accept. The wire
+// allows any 32 bit scale, but 10^scale for the largest of them would
+// take gigabytes to compute.
+const max_decimal_scale = 1 << 16
+
+// Rat returns the exact value of the decimal. It fails if the scale is
+// beyond ±65536.
+func (decimal Decimal) Rat() (*big.Rat, error) {
+	if abs64(int64(decimal.Scale)) > max_decimal_scale {
+		return nil, new_error_with_message(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE, "decimal scale out of range: "+strconv.FormatInt(int64(decimal.Scale), 10))
+	}
+	r := new(big.Rat).SetInt(decimal.unscaled())
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(abs64(int64(decimal.Scale))), nil)
+	if decimal.Scale >= 0 {
+		return r.Quo(r, new(big.Rat).SetInt(scale)), nil
+	}
+	return r.Mul(r, new(big.Rat).SetInt(scale)), nil
+}
+
+// Float returns the value of the decimal rounded to prec bits of mantissa.
+// It fails where Rat does.
+func (decimal Decimal) Float(prec uint) (*big.Float, error) {
+	r, err := decimal.Rat()
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Float).SetPrec(prec).SetRat(r), nil
+}
+
+// String formats the decimal with Scale digits after the point, or, for a
+// negative scale or one too large to write out, as the unscaled value and
+// an exponent, such as "1E+3". ParseDecimal reads it back with the same
+// scale.
+func (decimal Decimal) String() string {
+	digits := decimal.unscaled().String()
+	sign := ""
+	if digits[0] == '-' {
+		sign, digits = "-", digits[1:]
+	}
+
+	if decimal.Scale == 0 {
+		return sign + digits
+	}
+	if decimal.Scale < 0 {
+		return sign + digits + "E+" + strconv.FormatInt(-int64(decimal.Scale), 10)
+	}
+	if decimal.Scale > max_decimal_scale {
+		return sign + digits + "E-" + strconv.FormatInt(int64(decimal.Scale), 10)
+	}
+	scale := int(decimal.Scale)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+func (decimal Decimal) MarshalText() ([]byte, error) {
+	return []byte(decimal.String()), nil
+}
+
+func (decimal *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*decimal = parsed
+	return nil
+}
+
+// float_decimal converts f to the shortest Decimal that reads back as f.
+func float_decimal(f *big.Float) (Decimal, error) {
+	if f.IsInf() {
+		return Decimal{}, new_error_with_message(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE, "cannot bind infinite decimal")
+	}
+	return ParseDecimal(f.Text('e', -1))
+}
+
+// varint_bytes encodes x as a two's-complement big-endian integer in the
+// fewest bytes that keep its sign.
+func varint_bytes(x *big.Int) []byte {
+	if x.Sign() >= 0 {
+		return x.FillBytes(make([]byte, x.BitLen()/8+1))
+	}
+	// -x-1 has the same bits as x, inverted; it needs one sign bit more.
+	n := new(big.Int).Not(x).BitLen()/8 + 1
+	y := new(big.Int).Lsh(big.NewInt(1), uint(8*n))
+	return y.Add(y, x).FillBytes(make([]byte, n))
+}
+
+func varint_from_bytes(b []byte) *big.Int {
+	x := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		x.Sub(x, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+	}
+	return x
+}
+
+func abs64(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
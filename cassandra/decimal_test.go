@@ -0,0 +1,131 @@
+package cassandra
+
+import "bytes"
+import "math"
+import "math/big"
+import "testing"
+
+func TestVarintBytes(t *testing.T) {
+	tests := []struct {
+		x    int64
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{1, []byte{0x01}},
+		{127, []byte{0x7F}},
+		{128, []byte{0x00, 0x80}},
+		{255, []byte{0x00, 0xFF}},
+		{256, []byte{0x01, 0x00}},
+		{-1, []byte{0xFF}},
+		{-128, []byte{0x80}},
+		{-129, []byte{0xFF, 0x7F}},
+		{-256, []byte{0xFF, 0x00}},
+		{-32768, []byte{0x80, 0x00}},
+	}
+
+	for _, test := range tests {
+		got := varint_bytes(big.NewInt(test.x))
+		if !bytes.Equal(got, test.want) {
+			t.Errorf("varint_bytes(%d) = % X, want % X", test.x, got, test.want)
+		}
+		if back := varint_from_bytes(test.want); back.Int64() != test.x {
+			t.Errorf("varint_from_bytes(% X) = %v, want %d", test.want, back, test.x)
+		}
+	}
+}
+
+func TestVarintRoundTrip(t *testing.T) {
+	huge, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	for _, x := range []*big.Int{huge, new(big.Int).Neg(huge), new(big.Int).Lsh(big.NewInt(1), 64)} {
+		if back := varint_from_bytes(varint_bytes(x)); back.Cmp(x) != 0 {
+			t.Errorf("varint round trip of %v gave %v", x, back)
+		}
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		s        string
+		unscaled int64
+		scale    int32
+		text     string
+	}{
+		{"-12.50", -1250, 2, "-12.50"},
+		{"1e3", 1, -3, "1E+3"},
+		{"12E+2", 12, -2, "12E+2"},
+		{"0.00", 0, 2, "0.00"},
+		{"0", 0, 0, "0"},
+		{"1.5e-3", 15, 4, "0.0015"},
+		{"+7", 7, 0, "7"},
+		{".5", 5, 1, "0.5"},
+	}
+
+	for _, test := range tests {
+		decimal, err := ParseDecimal(test.s)
+		if err != nil {
+			t.Errorf("ParseDecimal(%q): %v", test.s, err)
+			continue
+		}
+		if decimal.Unscaled.Int64() != test.unscaled || decimal.Scale != test.scale {
+			t.Errorf("ParseDecimal(%q) = %v×10^-%d, want %d×10^-%d",
+				test.s, decimal.Unscaled, decimal.Scale, test.unscaled, test.scale)
+		}
+		if got := decimal.String(); got != test.text {
+			t.Errorf("ParseDecimal(%q).String() = %q, want %q", test.s, got, test.text)
+		}
+
+		again, err := ParseDecimal(decimal.String())
+		if err != nil || again.Unscaled.Cmp(decimal.Unscaled) != 0 || again.Scale != decimal.Scale {
+			t.Errorf("ParseDecimal(%q) does not round-trip: %v, %v", test.s, again, err)
+		}
+	}
+
+	for _, s := range []string{"", "-", "1.2.3", "1e", "e5", "--1", "1_000", "0x10"} {
+		if _, err := ParseDecimal(s); err == nil {
+			t.Errorf("ParseDecimal(%q) succeeded", s)
+		}
+	}
+}
+
+func TestDecimalZeroValue(t *testing.T) {
+	var decimal Decimal
+	if got := decimal.String(); got != "0" {
+		t.Errorf("Decimal{}.String() = %q, want \"0\"", got)
+	}
+	if got := varint_bytes(decimal.unscaled()); !bytes.Equal(got, []byte{0x00}) {
+		t.Errorf("Decimal{} encodes as % X, want 00", got)
+	}
+}
+
+func TestDecimalLargeScale(t *testing.T) {
+	tests := []struct {
+		scale int32
+		ok    bool
+	}{
+		{max_decimal_scale, true},
+		{-max_decimal_scale, true},
+		{max_decimal_scale + 1, false},
+		{-max_decimal_scale - 1, false},
+		{math.MaxInt32, false},
+		{math.MinInt32, false},
+	}
+
+	for _, test := range tests {
+		decimal := Decimal{Unscaled: big.NewInt(-125), Scale: test.scale}
+		r, err := decimal.Rat()
+		if test.ok != (err == nil) {
+			t.Errorf("Rat() with scale %d: %v", test.scale, err)
+		}
+		if _, ferr := decimal.Float(64); (ferr == nil) != (err == nil) {
+			t.Errorf("Float() and Rat() disagree for scale %d: %v, %v", test.scale, ferr, err)
+		}
+		if test.ok && r == nil {
+			t.Errorf("Rat() with scale %d returned nil", test.scale)
+		}
+
+		again, err := ParseDecimal(decimal.String())
+		if err != nil || again.Scale != decimal.Scale || again.Unscaled.Cmp(decimal.Unscaled) != 0 {
+			t.Errorf("scale %d does not round-trip: %v", test.scale, err)
+		}
+	}
+}
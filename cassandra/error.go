@@ -0,0 +1,261 @@
+package cassandra
+
+// #include <cassandra.h>
+import "C"
+import "reflect"
+
+// Error is the error type returned by every call in this package that can
+// fail. Source and Code hold the package's CASS_ERROR_SOURCE_* and
+// CASS_ERROR_* constants, Raw holds the driver's CassError value. Scans
+// into the wrong Go type fail with a TypeMismatchError instead.
+type Error struct {
+	Source  int
+	Code    int
+	Message string
+	Raw     uint32
+}
+
+var (
+	ErrBadParams           = sentinel(C.CASS_ERROR_LIB_BAD_PARAMS)
+	ErrNoStreams           = sentinel(C.CASS_ERROR_LIB_NO_STREAMS)
+	ErrUnableToInit        = sentinel(C.CASS_ERROR_LIB_UNABLE_TO_INIT)
+	ErrMessageEncode       = sentinel(C.CASS_ERROR_LIB_MESSAGE_ENCODE)
+	ErrHostResolution      = sentinel(C.CASS_ERROR_LIB_HOST_RESOLUTION)
+	ErrUnexpectedResponse  = sentinel(C.CASS_ERROR_LIB_UNEXPECTED_RESPONSE)
+	ErrRequestQueueFull    = sentinel(C.CASS_ERROR_LIB_REQUEST_QUEUE_FULL)
+	ErrNoAvailableIoThread = sentinel(C.CASS_ERROR_LIB_NO_AVAILABLE_IO_THREAD)
+	ErrWriteError          = sentinel(C.CASS_ERROR_LIB_WRITE_ERROR)
+	ErrNoHostsAvailable    = sentinel(C.CASS_ERROR_LIB_NO_HOSTS_AVAILABLE)
+	ErrIndexOutOfBounds    = sentinel(C.CASS_ERROR_LIB_INDEX_OUT_OF_BOUNDS)
+	ErrInvalidItemCount    = sentinel(C.CASS_ERROR_LIB_INVALID_ITEM_COUNT)
+	ErrInvalidValueType    = sentinel(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE)
+	ErrRequestTimedOut     = sentinel(C.CASS_ERROR_LIB_REQUEST_TIMED_OUT)
+	ErrUnableToSetKeyspace = sentinel(C.CASS_ERROR_LIB_UNABLE_TO_SET_KEYSPACE)
+	ErrCallbackAlreadySet  = sentinel(C.CASS_ERROR_LIB_CALLBACK_ALREADY_SET)
+	ErrInvalidStatement    = sentinel(C.CASS_ERROR_LIB_INVALID_STATEMENT_TYPE)
+	ErrNameDoesNotExist    = sentinel(C.CASS_ERROR_LIB_NAME_DOES_NOT_EXIST)
+	ErrUnableToDetermine   = sentinel(C.CASS_ERROR_LIB_UNABLE_TO_DETERMINE_PROTOCOL)
+	ErrNullValue           = sentinel(C.CASS_ERROR_LIB_NULL_VALUE)
+	ErrNotImplemented      = sentinel(C.CASS_ERROR_LIB_NOT_IMPLEMENTED)
+	ErrUnableToConnect     = sentinel(C.CASS_ERROR_LIB_UNABLE_TO_CONNECT)
+	ErrUnableToClose       = sentinel(C.CASS_ERROR_LIB_UNABLE_TO_CLOSE)
+
+	ErrServerError     = sentinel(C.CASS_ERROR_SERVER_SERVER_ERROR)
+	ErrProtocolError   = sentinel(C.CASS_ERROR_SERVER_PROTOCOL_ERROR)
+	ErrBadCredentials  = sentinel(C.CASS_ERROR_SERVER_BAD_CREDENTIALS)
+	ErrUnavailable     = sentinel(C.CASS_ERROR_SERVER_UNAVAILABLE)
+	ErrOverloaded      = sentinel(C.CASS_ERROR_SERVER_OVERLOADED)
+	ErrIsBootstrapping = sentinel(C.CASS_ERROR_SERVER_IS_BOOTSTRAPPING)
+	ErrTruncateError   = sentinel(C.CASS_ERROR_SERVER_TRUNCATE_ERROR)
+	ErrWriteTimeout    = sentinel(C.CASS_ERROR_SERVER_WRITE_TIMEOUT)
+	ErrReadTimeout     = sentinel(C.CASS_ERROR_SERVER_READ_TIMEOUT)
+	ErrSyntaxError     = sentinel(C.CASS_ERROR_SERVER_SYNTAX_ERROR)
+	ErrUnauthorized    = sentinel(C.CASS_ERROR_SERVER_UNAUTHORIZED)
+	ErrInvalidQuery    = sentinel(C.CASS_ERROR_SERVER_INVALID_QUERY)
+	ErrConfigError     = sentinel(C.CASS_ERROR_SERVER_CONFIG_ERROR)
+	ErrAlreadyExists   = sentinel(C.CASS_ERROR_SERVER_ALREADY_EXISTS)
+	ErrUnprepared      = sentinel(C.CASS_ERROR_SERVER_UNPREPARED)
+
+	ErrInvalidCert       = sentinel(C.CASS_ERROR_SSL_INVALID_CERT)
+	ErrInvalidPrivateKey = sentinel(C.CASS_ERROR_SSL_INVALID_PRIVATE_KEY)
+	ErrNoPeerCert        = sentinel(C.CASS_ERROR_SSL_NO_PEER_CERT)
+	ErrInvalidPeerCert   = sentinel(C.CASS_ERROR_SSL_INVALID_PEER_CERT)
+	ErrIdentityMismatch  = sentinel(C.CASS_ERROR_SSL_IDENTITY_MISMATCH)
+)
+
+func sentinel(rc C.CassError) *Error {
+	return new_error(rc)
+}
+
+func new_error(rc C.CassError) *Error {
+	return new_error_with_message(rc, C.GoString(C.cass_error_desc(rc)))
+}
+
+func new_error_with_message(rc C.CassError, message string) *Error {
+	return &Error{
+		Source:  error_source(rc),
+		Code:    error_code(rc),
+		Message: message,
+		Raw:     uint32(rc),
+	}
+}
+
+func bad_params(msg string) error {
+	return new_error_with_message(C.CASS_ERROR_LIB_BAD_PARAMS, msg)
+}
+
+// TypeMismatchError reports a column that cannot be scanned into the Go
+// type it was given. It matches ErrInvalidValueType with errors.Is.
+type TypeMismatchError struct {
+	ValueType int // one of the CASS_VALUE_TYPE_* constants
+	GoType    string
+}
+
+func (err *TypeMismatchError) Error() string {
+	return "cannot scan " + ValueTypeName(err.ValueType) + " into " + err.GoType
+}
+
+func (err *TypeMismatchError) Unwrap() error {
+	return ErrInvalidValueType
+}
+
+func type_mismatch(value *C.CassValue, v interface{}) error {
+	go_type := "nil"
+	if t := reflect.TypeOf(v); t != nil {
+		go_type = t.String()
+	}
+	value_type := CASS_VALUE_TYPE_UNKNOWN
+	if value != nil {
+		value_type = int(C.cass_value_type(value))
+	}
+	return &TypeMismatchError{ValueType: value_type, GoType: go_type}
+}
+
+// scan_error reports rc from reading value into v, turning the driver's
+// type errors into a TypeMismatchError.
+func scan_error(rc C.CassError, value *C.CassValue, v interface{}) error {
+	if rc == C.CASS_ERROR_LIB_INVALID_VALUE_TYPE {
+		return type_mismatch(value, v)
+	}
+	return new_error(rc)
+}
+
+func (err *Error) Error() string {
+	return err.Message
+}
+
+// Is reports whether target is an *Error with the same driver error code,
+// so that errors.Is(err, ErrUnavailable) matches any unavailable error
+// regardless of its message.
+func (err *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Raw == err.Raw
+}
+
+func error_source(rc C.CassError) int {
+	source := (rc >> 24)
+
+	switch source {
+	case C.CASS_ERROR_SOURCE_NONE:
+		return CASS_OK
+	case C.CASS_ERROR_SOURCE_LIB:
+		return CASS_ERROR_SOURCE_LIB
+	case C.CASS_ERROR_SOURCE_SERVER:
+		return CASS_ERROR_SOURCE_SERVER
+	case C.CASS_ERROR_SOURCE_SSL:
+		return CASS_ERROR_SOURCE_SSL
+	case C.CASS_ERROR_SOURCE_COMPRESSION:
+		return CASS_ERROR_SOURCE_COMPRESSION
+	}
+	return CASS_ERROR_SOURCE_NONE
+}
+
+func error_code(rc C.CassError) int {
+	source := error_source(rc)
+
+	if source == CASS_ERROR_SOURCE_NONE {
+		return CASS_OK
+	} else if source == CASS_ERROR_SOURCE_LIB {
+		switch rc {
+		case CASS_OK:
+			return CASS_OK
+		case C.CASS_ERROR_LIB_BAD_PARAMS:
+			return CASS_ERROR_LIB_BAD_PARAMS
+		case C.CASS_ERROR_LIB_NO_STREAMS:
+			return CASS_ERROR_LIB_NO_STREAMS
+		case C.CASS_ERROR_LIB_UNABLE_TO_INIT:
+			return CASS_ERROR_LIB_UNABLE_TO_INIT
+		case C.CASS_ERROR_LIB_MESSAGE_ENCODE:
+			return CASS_ERROR_LIB_MESSAGE_ENCODE
+		case C.CASS_ERROR_LIB_HOST_RESOLUTION:
+			return CASS_ERROR_LIB_HOST_RESOLUTION
+		case C.CASS_ERROR_LIB_UNEXPECTED_RESPONSE:
+			return CASS_ERROR_LIB_UNEXPECTED_RESPONSE
+		case C.CASS_ERROR_LIB_REQUEST_QUEUE_FULL:
+			return CASS_ERROR_LIB_REQUEST_QUEUE_FULL
+		case C.CASS_ERROR_LIB_NO_AVAILABLE_IO_THREAD:
+			return CASS_ERROR_LIB_NO_AVAILABLE_IO_THREAD
+		case C.CASS_ERROR_LIB_WRITE_ERROR:
+			return CASS_ERROR_LIB_WRITE_ERROR
+		case C.CASS_ERROR_LIB_NO_HOSTS_AVAILABLE:
+			return CASS_ERROR_LIB_NO_HOSTS_AVAILABLE
+		case C.CASS_ERROR_LIB_INDEX_OUT_OF_BOUNDS:
+			return CASS_ERROR_LIB_INDEX_OUT_OF_BOUNDS
+		case C.CASS_ERROR_LIB_INVALID_ITEM_COUNT:
+			return CASS_ERROR_LIB_INVALID_ITEM_COUNT
+		case C.CASS_ERROR_LIB_INVALID_VALUE_TYPE:
+			return CASS_ERROR_LIB_INVALID_VALUE_TYPE
+		case C.CASS_ERROR_LIB_REQUEST_TIMED_OUT:
+			return CASS_ERROR_LIB_REQUEST_TIMED_OUT
+		case C.CASS_ERROR_LIB_UNABLE_TO_SET_KEYSPACE:
+			return CASS_ERROR_LIB_UNABLE_TO_SET_KEYSPACE
+		case C.CASS_ERROR_LIB_CALLBACK_ALREADY_SET:
+			return CASS_ERROR_LIB_CALLBACK_ALREADY_SET
+		case C.CASS_ERROR_LIB_INVALID_STATEMENT_TYPE:
+			return CASS_ERROR_LIB_INVALID_STATEMENT_TYPE
+		case C.CASS_ERROR_LIB_NAME_DOES_NOT_EXIST:
+			return CASS_ERROR_LIB_NAME_DOES_NOT_EXIST
+		case C.CASS_ERROR_LIB_UNABLE_TO_DETERMINE_PROTOCOL:
+			return CASS_ERROR_LIB_UNABLE_TO_DETERMINE_PROTOCOL
+		case C.CASS_ERROR_LIB_NULL_VALUE:
+			return CASS_ERROR_LIB_NULL_VALUE
+		case C.CASS_ERROR_LIB_NOT_IMPLEMENTED:
+			return CASS_ERROR_LIB_NOT_IMPLEMENTED
+		case C.CASS_ERROR_LIB_UNABLE_TO_CONNECT:
+			return CASS_ERROR_LIB_UNABLE_TO_CONNECT
+		case C.CASS_ERROR_LIB_UNABLE_TO_CLOSE:
+			return CASS_ERROR_LIB_UNABLE_TO_CLOSE
+		}
+	} else if source == CASS_ERROR_SOURCE_SERVER {
+		switch rc {
+		case C.CASS_OK:
+			return C.CASS_OK
+		case C.CASS_ERROR_SERVER_SERVER_ERROR:
+			return CASS_ERROR_SERVER_SERVER_ERROR
+		case C.CASS_ERROR_SERVER_PROTOCOL_ERROR:
+			return CASS_ERROR_SERVER_PROTOCOL_ERROR
+		case C.CASS_ERROR_SERVER_BAD_CREDENTIALS:
+			return CASS_ERROR_SERVER_BAD_CREDENTIALS
+		case C.CASS_ERROR_SERVER_UNAVAILABLE:
+			return CASS_ERROR_SERVER_UNAVAILABLE
+		case C.CASS_ERROR_SERVER_OVERLOADED:
+			return CASS_ERROR_SERVER_OVERLOADED
+		case C.CASS_ERROR_SERVER_IS_BOOTSTRAPPING:
+			return CASS_ERROR_SERVER_IS_BOOTSTRAPPING
+		case C.CASS_ERROR_SERVER_TRUNCATE_ERROR:
+			return CASS_ERROR_SERVER_TRUNCATE_ERROR
+		case C.CASS_ERROR_SERVER_WRITE_TIMEOUT:
+			return CASS_ERROR_SERVER_WRITE_TIMEOUT
+		case C.CASS_ERROR_SERVER_READ_TIMEOUT:
+			return CASS_ERROR_SERVER_READ_TIMEOUT
+		case C.CASS_ERROR_SERVER_SYNTAX_ERROR:
+			return CASS_ERROR_SERVER_SYNTAX_ERROR
+		case C.CASS_ERROR_SERVER_UNAUTHORIZED:
+			return CASS_ERROR_SERVER_UNAUTHORIZED
+		case C.CASS_ERROR_SERVER_INVALID_QUERY:
+			return CASS_ERROR_SERVER_INVALID_QUERY
+		case C.CASS_ERROR_SERVER_CONFIG_ERROR:
+			return CASS_ERROR_SERVER_CONFIG_ERROR
+		case C.CASS_ERROR_SERVER_ALREADY_EXISTS:
+			return CASS_ERROR_SERVER_ALREADY_EXISTS
+		case C.CASS_ERROR_SERVER_UNPREPARED:
+			return CASS_ERROR_SERVER_UNPREPARED
+		}
+	} else if source == CASS_ERROR_SOURCE_SSL {
+		switch rc {
+		case CASS_OK:
+			return C.CASS_OK
+		case C.CASS_ERROR_SSL_INVALID_CERT:
+			return CASS_ERROR_SSL_INVALID_CERT
+		case C.CASS_ERROR_SSL_INVALID_PRIVATE_KEY:
+			return CASS_ERROR_SSL_INVALID_PRIVATE_KEY
+		case C.CASS_ERROR_SSL_NO_PEER_CERT:
+			return CASS_ERROR_SSL_NO_PEER_CERT
+		case C.CASS_ERROR_SSL_INVALID_PEER_CERT:
+			return CASS_ERROR_SSL_INVALID_PEER_CERT
+		case C.CASS_ERROR_SSL_IDENTITY_MISMATCH:
+			return CASS_ERROR_SSL_IDENTITY_MISMATCH
+		}
+	}
+	return CASS_ERROR_LIB_UNEXPECTED_RESPONSE
+}
@@ -0,0 +1,49 @@
+package cassandra
+
+// Functions the driver calls back into. cgo does not allow C definitions in
+// the preamble of a file with //export directives, so the C shims that pass
+// these to the driver live next to the Go code that uses them.
+
+// #include <stdint.h>
+// #include <cassandra.h>
+import "C"
+import "runtime/cgo"
+import "unsafe"
+
+//export go_future_callback
+func go_future_callback(cfuture *C.CassFuture, data unsafe.Pointer) {
+	handle := cgo.Handle(uintptr(data))
+	future := handle.Value().(*Future)
+	handle.Delete()
+	future.complete()
+}
+
+//export go_auth_initial
+func go_auth_initial(auth *C.CassAuthenticator, handle C.uintptr_t) {
+	authenticator_initial(auth, handle)
+}
+
+//export go_auth_challenge
+func go_auth_challenge(auth *C.CassAuthenticator, token *C.char, size C.size_t) {
+	authenticator_challenge(auth, C.GoBytes(unsafe.Pointer(token), C.int(size)))
+}
+
+//export go_auth_success
+func go_auth_success(auth *C.CassAuthenticator, token *C.char, size C.size_t) {
+	authenticator_success(auth, C.GoBytes(unsafe.Pointer(token), C.int(size)))
+}
+
+//export go_auth_cleanup
+func go_auth_cleanup(auth *C.CassAuthenticator) {
+	authenticator_cleanup(auth)
+}
+
+//export go_auth_data_cleanup
+func go_auth_data_cleanup(handle C.uintptr_t) {
+	cgo.Handle(handle).Delete()
+}
+
+//export go_log_callback
+func go_log_callback(message *C.CassLogMessage) {
+	enqueue_log(message)
+}
@@ -0,0 +1,116 @@
+package cassandra
+
+/*
+#include <stdint.h>
+#include <cassandra.h>
+
+extern void go_future_callback(CassFuture* future, void* data);
+
+static CassError set_future_callback(CassFuture* future, uintptr_t handle) {
+	return cass_future_set_callback(future, go_future_callback, (void*)handle);
+}
+*/
+import "C"
+import "runtime/cgo"
+
+// Done returns a channel that is closed once the future completes. The
+// first call registers a completion callback with the driver, so no
+// goroutine or OS thread is parked waiting for the result.
+func (future *Future) Done() <-chan struct{} {
+	future.mu.Lock()
+	if future.done == nil {
+		future.done = make(chan struct{})
+		if future.completed {
+			close(future.done)
+		}
+	}
+	done := future.done
+	future.mu.Unlock()
+
+	future.watch()
+	return done
+}
+
+// OnComplete arranges for fn to be called once the future completes. fn
+// runs on one of the driver's IO threads and must not block; hand off to a
+// goroutine for anything slow. If the future has already completed fn is
+// called immediately.
+func (future *Future) OnComplete(fn func(*Future)) {
+	future.mu.Lock()
+	if future.completed {
+		future.mu.Unlock()
+		fn(future)
+		return
+	}
+	future.callbacks = append(future.callbacks, fn)
+	future.mu.Unlock()
+
+	future.watch()
+}
+
+func (future *Future) watch() {
+	future.mu.Lock()
+	if future.watching {
+		future.mu.Unlock()
+		return
+	}
+	future.watching = true
+	future.mu.Unlock()
+
+	// The driver only sees an integer handle, never a Go pointer. The
+	// handle keeps the Future reachable until the callback releases it.
+	handle := cgo.NewHandle(future)
+	rc := C.set_future_callback(future.cptr, C.uintptr_t(handle))
+	if rc != C.CASS_OK {
+		handle.Delete()
+		go func() {
+			C.cass_future_wait(future.cptr)
+			future.complete()
+		}()
+	}
+}
+
+func (future *Future) complete() {
+	future.mu.Lock()
+	future.completed = true
+	if future.done != nil {
+		close(future.done)
+	}
+	callbacks := future.callbacks
+	future.callbacks = nil
+	future.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn(future)
+	}
+}
+
+// wait blocks until a chained future has taken over its last attempt. Other
+// futures are waited for by the driver call that reads them.
+func (future *Future) wait() {
+	if future.chained {
+		<-future.Done()
+	}
+}
+
+func (future *Future) is_closed() bool {
+	future.mu.Lock()
+	defer future.mu.Unlock()
+	return future.closed
+}
+
+// adopt completes a chained future with the driver future of last, or
+// frees last if the chained future was closed in the meantime.
+func (future *Future) adopt(last *Future) {
+	future.mu.Lock()
+	if future.closed {
+		future.mu.Unlock()
+		last.Close()
+		return
+	}
+	future.cptr, last.cptr = last.cptr, nil
+	future.mu.Unlock()
+
+	last.Close()
+	future.complete()
+}
@@ -0,0 +1,22 @@
+package cassandra
+
+// #include <cassandra.h>
+import "C"
+import "net"
+import "unsafe"
+
+// new_inet converts ip to the driver's representation. IPv4 addresses,
+// including IPv4-mapped IPv6 ones, become 4 byte inets.
+func new_inet(ip net.IP) (C.CassInet, error) {
+	if ip4 := ip.To4(); ip4 != nil {
+		return C.cass_inet_init_v4((*C.cass_uint8_t)(unsafe.Pointer(&ip4[0]))), nil
+	}
+	if len(ip) == net.IPv6len {
+		return C.cass_inet_init_v6((*C.cass_uint8_t)(unsafe.Pointer(&ip[0]))), nil
+	}
+	return C.CassInet{}, new_error_with_message(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE, "invalid IP address: "+ip.String())
+}
+
+func inet_ip(inet C.CassInet) net.IP {
+	return net.IP(C.GoBytes(unsafe.Pointer(&inet.address[0]), C.int(inet.address_length)))
+}
@@ -0,0 +1,23 @@
+//go:build !cassandra_debug
+
+package cassandra
+
+// Leak tracking is compiled out unless the cassandra_debug build tag is set.
+
+func record_allocation(obj interface{}) {}
+
+func forget_allocation(obj interface{}) {}
+
+func record_leak(obj interface{}) {}
+
+// Leaks returns the allocation stack traces of objects that were finalized
+// without being closed. It always returns nil unless the package is built
+// with the cassandra_debug tag.
+func Leaks() []string {
+	return nil
+}
+
+// LeakCount returns len(Leaks()).
+func LeakCount() int {
+	return 0
+}
@@ -0,0 +1,57 @@
+//go:build cassandra_debug
+
+package cassandra
+
+import "fmt"
+import "reflect"
+import "runtime/debug"
+import "sync"
+
+// With the cassandra_debug tag every tracked object records the stack that
+// allocated it. Objects closed explicitly forget their stack; objects that
+// reach their finalizer instead move it to the leak list.
+
+var allocations = struct {
+	sync.Mutex
+	stacks map[uintptr]string
+	leaks  []string
+}{stacks: make(map[uintptr]string)}
+
+func record_allocation(obj interface{}) {
+	stack := fmt.Sprintf("%T allocated at:\n%s", obj, debug.Stack())
+	allocations.Lock()
+	allocations.stacks[reflect.ValueOf(obj).Pointer()] = stack
+	allocations.Unlock()
+}
+
+func forget_allocation(obj interface{}) {
+	allocations.Lock()
+	delete(allocations.stacks, reflect.ValueOf(obj).Pointer())
+	allocations.Unlock()
+}
+
+func record_leak(obj interface{}) {
+	key := reflect.ValueOf(obj).Pointer()
+	allocations.Lock()
+	if stack, ok := allocations.stacks[key]; ok {
+		allocations.leaks = append(allocations.leaks, stack)
+		delete(allocations.stacks, key)
+	}
+	allocations.Unlock()
+}
+
+// Leaks returns the allocation stack traces of objects that were finalized
+// without being closed. Call runtime.GC first to give finalizers a chance
+// to run.
+func Leaks() []string {
+	allocations.Lock()
+	defer allocations.Unlock()
+	return append([]string(nil), allocations.leaks...)
+}
+
+// LeakCount returns len(Leaks()).
+func LeakCount() int {
+	allocations.Lock()
+	defer allocations.Unlock()
+	return len(allocations.leaks)
+}
@@ -0,0 +1,180 @@
+package cassandra
+
+// #include <stdlib.h>
+// #include <cassandra.h>
+import "C"
+import "math"
+import "net"
+import "strings"
+import "time"
+import "unsafe"
+
+// SetLoadBalanceRoundRobin spreads requests over all hosts in turn. This is
+// the default.
+func (cluster *Cluster) SetLoadBalanceRoundRobin() {
+	C.cass_cluster_set_load_balance_round_robin(cluster.cptr)
+}
+
+// SetLoadBalanceDCAware prefers hosts in localDC. usedHostsPerRemoteDC hosts
+// of each remote datacenter are tried once the local ones are exhausted;
+// allowRemoteForLocalCL lets them serve LOCAL_ONE and LOCAL_QUORUM requests
+// too.
+func (cluster *Cluster) SetLoadBalanceDCAware(localDC string, usedHostsPerRemoteDC int, allowRemoteForLocalCL bool) error {
+	if localDC == "" {
+		return bad_params("local datacenter must not be empty")
+	}
+	if usedHostsPerRemoteDC < 0 || uint64(usedHostsPerRemoteDC) > math.MaxUint32 {
+		return bad_params("used hosts per remote datacenter out of range")
+	}
+
+	cs := C.CString(localDC)
+	defer C.free(unsafe.Pointer(cs))
+	rc := C.cass_cluster_set_load_balance_dc_aware(cluster.cptr, cs,
+		C.unsigned(usedHostsPerRemoteDC), cass_bool(allowRemoteForLocalCL))
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+// SetTokenAwareRouting sends requests to a replica of the partition being
+// queried when the routing key is known. It is on by default.
+func (cluster *Cluster) SetTokenAwareRouting(enabled bool) {
+	C.cass_cluster_set_token_aware_routing(cluster.cptr, cass_bool(enabled))
+}
+
+// SetTokenAwareRoutingShuffleReplicas picks a random replica rather than
+// always the first.
+func (cluster *Cluster) SetTokenAwareRoutingShuffleReplicas(enabled bool) {
+	C.cass_cluster_set_token_aware_routing_shuffle_replicas(cluster.cptr, cass_bool(enabled))
+}
+
+// SetLatencyAwareRouting avoids hosts that respond much slower than the
+// fastest one. It is off by default.
+func (cluster *Cluster) SetLatencyAwareRouting(enabled bool) {
+	C.cass_cluster_set_latency_aware_routing(cluster.cptr, cass_bool(enabled))
+}
+
+type LatencyAwareSettings struct {
+	// ExclusionThreshold is how many times slower than the fastest host a
+	// host may be before it is avoided.
+	ExclusionThreshold float64
+
+	// Scale weighs older latencies against newer ones.
+	Scale time.Duration
+
+	// RetryPeriod is how long an avoided host is left alone before it is
+	// tried again.
+	RetryPeriod time.Duration
+
+	// UpdateRate is how often the fastest latency is recomputed.
+	UpdateRate time.Duration
+
+	// MinMeasured is how many requests a host must have served before its
+	// latency is taken into account.
+	MinMeasured uint64
+}
+
+// DefaultLatencyAwareSettings are the settings the driver starts with.
+var DefaultLatencyAwareSettings = LatencyAwareSettings{
+	ExclusionThreshold: 2.0,
+	Scale:              100 * time.Millisecond,
+	RetryPeriod:        10 * time.Second,
+	UpdateRate:         100 * time.Millisecond,
+	MinMeasured:        50,
+}
+
+// SetLatencyAwareRoutingSettings tunes latency-aware routing. Durations are
+// rounded down to whole milliseconds.
+func (cluster *Cluster) SetLatencyAwareRoutingSettings(settings LatencyAwareSettings) error {
+	if math.IsNaN(settings.ExclusionThreshold) || math.IsInf(settings.ExclusionThreshold, 0) || settings.ExclusionThreshold < 1.0 {
+		return bad_params("latency exclusion threshold must be at least 1.0")
+	}
+	if settings.Scale < time.Millisecond {
+		return bad_params("latency scale must be at least 1ms")
+	}
+	if settings.RetryPeriod < 0 {
+		return bad_params("latency retry period must not be negative")
+	}
+	if settings.UpdateRate < time.Millisecond {
+		return bad_params("latency update rate must be at least 1ms")
+	}
+
+	C.cass_cluster_set_latency_aware_routing_settings(cluster.cptr,
+		C.cass_double_t(settings.ExclusionThreshold),
+		C.cass_uint64_t(settings.Scale/time.Millisecond),
+		C.cass_uint64_t(settings.RetryPeriod/time.Millisecond),
+		C.cass_uint64_t(settings.UpdateRate/time.Millisecond),
+		C.cass_uint64_t(settings.MinMeasured))
+	return nil
+}
+
+// SetHostAllowList restricts connections to the given IP addresses. With no
+// hosts the restriction is lifted.
+func (cluster *Cluster) SetHostAllowList(hosts ...string) error {
+	list, err := host_list(hosts)
+	if err != nil {
+		return err
+	}
+	cs := C.CString(list)
+	defer C.free(unsafe.Pointer(cs))
+	C.cass_cluster_set_whitelist_filtering(cluster.cptr, cs)
+	return nil
+}
+
+// SetHostDenyList prevents connections to the given IP addresses. With no
+// hosts the restriction is lifted.
+func (cluster *Cluster) SetHostDenyList(hosts ...string) error {
+	list, err := host_list(hosts)
+	if err != nil {
+		return err
+	}
+	cs := C.CString(list)
+	defer C.free(unsafe.Pointer(cs))
+	C.cass_cluster_set_blacklist_filtering(cluster.cptr, cs)
+	return nil
+}
+
+// SetDCAllowList restricts connections to hosts in the given datacenters.
+// With no datacenters the restriction is lifted.
+func (cluster *Cluster) SetDCAllowList(dcs ...string) error {
+	list, err := dc_list(dcs)
+	if err != nil {
+		return err
+	}
+	cs := C.CString(list)
+	defer C.free(unsafe.Pointer(cs))
+	C.cass_cluster_set_whitelist_dc_filtering(cluster.cptr, cs)
+	return nil
+}
+
+// SetDCDenyList prevents connections to hosts in the given datacenters.
+// With no datacenters the restriction is lifted.
+func (cluster *Cluster) SetDCDenyList(dcs ...string) error {
+	list, err := dc_list(dcs)
+	if err != nil {
+		return err
+	}
+	cs := C.CString(list)
+	defer C.free(unsafe.Pointer(cs))
+	C.cass_cluster_set_blacklist_dc_filtering(cluster.cptr, cs)
+	return nil
+}
+
+func host_list(hosts []string) (string, error) {
+	for _, host := range hosts {
+		if net.ParseIP(host) == nil {
+			return "", bad_params("not an IP address: " + host)
+		}
+	}
+	return strings.Join(hosts, ","), nil
+}
+
+func dc_list(dcs []string) (string, error) {
+	for _, dc := range dcs {
+		if dc == "" || strings.ContainsAny(dc, ",\x00") {
+			return "", bad_params("invalid datacenter name: " + dc)
+		}
+	}
+	return strings.Join(dcs, ","), nil
+}
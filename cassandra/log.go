@@ -0,0 +1,144 @@
+package cassandra
+
+/*
+#include <cassandra.h>
+
+extern void go_log_callback(CassLogMessage* message);
+
+static void log_callback(const CassLogMessage* message, void* data) {
+	go_log_callback((CassLogMessage*)message);
+}
+
+static void set_log_callback() {
+	cass_log_set_callback(log_callback, NULL);
+}
+*/
+import "C"
+import "context"
+import "fmt"
+import "log/slog"
+import "os"
+import "sync"
+import "sync/atomic"
+import "time"
+
+// LogMessage is one entry of the driver's log.
+type LogMessage struct {
+	Time     time.Time
+	Severity int32 // one of the CASS_LOG_* constants
+	File     string
+	Line     int
+	Function string
+	Message  string
+}
+
+func LogLevelName(level int32) string {
+	return C.GoString(C.cass_log_level_string(C.CassLogLevel(level)))
+}
+
+// The driver logs from its IO threads. Messages are queued and handed to
+// the logger by a single goroutine, so a slow logger never stalls a
+// request; when the queue is full, messages are dropped and counted.
+
+const log_queue_size = 1024
+
+type log_sink struct {
+	fn func(LogMessage)
+}
+
+var (
+	log_once    sync.Once
+	log_queue   = make(chan LogMessage, log_queue_size)
+	log_current atomic.Value
+	log_dropped atomic.Uint64
+)
+
+// SetLogger sends the driver's log to fn instead of standard error. fn is
+// called from a single goroutine, one message at a time. A nil fn restores
+// logging to standard error. Use SetLogLevel to choose what is logged.
+//
+// Make the first call before creating any cluster: the driver's log
+// callback cannot safely be installed once it may be logging. Later calls
+// may change fn at any time.
+func SetLogger(fn func(LogMessage)) {
+	log_current.Store(log_sink{fn})
+	log_once.Do(func() {
+		go drain_log()
+		C.set_log_callback()
+	})
+}
+
+// DroppedLogMessages returns how many messages have been dropped because
+// the logger could not keep up.
+func DroppedLogMessages() uint64 {
+	return log_dropped.Load()
+}
+
+func enqueue_log(cmsg *C.CassLogMessage) {
+	msg := LogMessage{
+		Time:     time.UnixMilli(int64(cmsg.time_ms)),
+		Severity: int32(cmsg.severity),
+		File:     C.GoString(cmsg.file),
+		Line:     int(cmsg.line),
+		Function: C.GoString(cmsg.function),
+		Message:  C.GoString(&cmsg.message[0]),
+	}
+	select {
+	case log_queue <- msg:
+	default:
+		log_dropped.Add(1)
+	}
+}
+
+func drain_log() {
+	for msg := range log_queue {
+		sink, _ := log_current.Load().(log_sink)
+		if sink.fn != nil {
+			sink.fn(msg)
+		} else {
+			log_stderr(msg)
+		}
+	}
+}
+
+func log_stderr(msg LogMessage) {
+	ms := msg.Time.UnixMilli()
+	fmt.Fprintf(os.Stderr, "%d.%03d [%s] (%s:%d:%s): %s\n", ms/1000, ms%1000,
+		LogLevelName(msg.Severity), msg.File, msg.Line, msg.Function, msg.Message)
+}
+
+// SlogLogger adapts logger for SetLogger. Messages keep the driver's
+// timestamp; the source location is added as the file, line and function
+// attributes.
+func SlogLogger(logger *slog.Logger) func(LogMessage) {
+	return func(msg LogMessage) {
+		ctx := context.Background()
+		level := slog_level(msg.Severity)
+		if !logger.Enabled(ctx, level) {
+			return
+		}
+		record := slog.NewRecord(msg.Time, level, msg.Message, 0)
+		record.AddAttrs(
+			slog.String("file", msg.File),
+			slog.Int("line", msg.Line),
+			slog.String("function", msg.Function),
+		)
+		logger.Handler().Handle(ctx, record)
+	}
+}
+
+func slog_level(severity int32) slog.Level {
+	switch severity {
+	case CASS_LOG_CRITICAL:
+		return slog.LevelError + 4
+	case CASS_LOG_ERROR:
+		return slog.LevelError
+	case CASS_LOG_WARN:
+		return slog.LevelWarn
+	case CASS_LOG_INFO:
+		return slog.LevelInfo
+	case CASS_LOG_DEBUG:
+		return slog.LevelDebug
+	}
+	return slog.LevelDebug - 4
+}
@@ -0,0 +1,179 @@
+// Package metrics exports the metrics of cassandra sessions to Prometheus
+// and expvar. Every metric is labelled with the name the session was added
+// under.
+//
+//	collector := metrics.NewCollector()
+//	collector.Add("users", session)
+//	prometheus.MustRegister(collector)
+//	collector.Publish("cassandra")
+package metrics
+
+import "expvar"
+import "sort"
+import "sync"
+
+import "github.com/mstump/golang-driver/cassandra"
+import "github.com/prometheus/client_golang/prometheus"
+
+const namespace = "cassandra"
+
+var (
+	latency_desc = prometheus.NewDesc(namespace+"_request_latency_seconds",
+		"Request latency over the driver's sampling window.", []string{"session", "quantile"}, nil)
+	latency_min_desc = prometheus.NewDesc(namespace+"_request_latency_min_seconds",
+		"Smallest request latency.", []string{"session"}, nil)
+	latency_max_desc = prometheus.NewDesc(namespace+"_request_latency_max_seconds",
+		"Largest request latency.", []string{"session"}, nil)
+	latency_mean_desc = prometheus.NewDesc(namespace+"_request_latency_mean_seconds",
+		"Mean request latency.", []string{"session"}, nil)
+	latency_stddev_desc = prometheus.NewDesc(namespace+"_request_latency_stddev_seconds",
+		"Standard deviation of request latency.", []string{"session"}, nil)
+	rate_desc = prometheus.NewDesc(namespace+"_request_rate",
+		"Requests per second, over the whole life of the session or a moving window.", []string{"session", "window"}, nil)
+
+	connections_desc = prometheus.NewDesc(namespace+"_connections",
+		"Open connections.", []string{"session"}, nil)
+	available_connections_desc = prometheus.NewDesc(namespace+"_available_connections",
+		"Connections able to take more requests.", []string{"session"}, nil)
+	exceeded_pending_requests_desc = prometheus.NewDesc(namespace+"_exceeded_pending_requests_water_mark_total",
+		"Times a connection passed its pending requests high water mark.", []string{"session"}, nil)
+	exceeded_write_bytes_desc = prometheus.NewDesc(namespace+"_exceeded_write_bytes_water_mark_total",
+		"Times a connection passed its write bytes high water mark.", []string{"session"}, nil)
+
+	connection_timeouts_desc = prometheus.NewDesc(namespace+"_connection_timeouts_total",
+		"Connection attempts that timed out.", []string{"session"}, nil)
+	pending_request_timeouts_desc = prometheus.NewDesc(namespace+"_pending_request_timeouts_total",
+		"Requests that timed out waiting for a connection.", []string{"session"}, nil)
+	request_timeouts_desc = prometheus.NewDesc(namespace+"_request_timeouts_total",
+		"Requests that timed out waiting for a response.", []string{"session"}, nil)
+
+	prepared_hits_desc = prometheus.NewDesc(namespace+"_prepared_cache_hits_total",
+		"Session.Query calls served from the prepared statement cache.", []string{"session"}, nil)
+	prepared_misses_desc = prometheus.NewDesc(namespace+"_prepared_cache_misses_total",
+		"Session.Query calls that had to prepare their query.", []string{"session"}, nil)
+	prepared_evictions_desc = prometheus.NewDesc(namespace+"_prepared_cache_evictions_total",
+		"Prepared statements dropped from the cache.", []string{"session"}, nil)
+)
+
+// Collector gathers the metrics of a set of named sessions. It implements
+// prometheus.Collector.
+type Collector struct {
+	mu       sync.RWMutex
+	sessions map[string]*cassandra.Session
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+func NewCollector() *Collector {
+	return &Collector{sessions: make(map[string]*cassandra.Session)}
+}
+
+// Add starts collecting the metrics of session under name, replacing any
+// session already added under that name.
+func (collector *Collector) Add(name string, session *cassandra.Session) {
+	collector.mu.Lock()
+	collector.sessions[name] = session
+	collector.mu.Unlock()
+}
+
+// Remove stops collecting the session added under name. Remove sessions
+// before closing them.
+func (collector *Collector) Remove(name string) {
+	collector.mu.Lock()
+	delete(collector.sessions, name)
+	collector.mu.Unlock()
+}
+
+// Snapshot returns the current metrics of every session, by name.
+func (collector *Collector) Snapshot() map[string]cassandra.Metrics {
+	collector.mu.RLock()
+	defer collector.mu.RUnlock()
+	snapshot := make(map[string]cassandra.Metrics, len(collector.sessions))
+	for name, session := range collector.sessions {
+		snapshot[name] = session.Metrics()
+	}
+	return snapshot
+}
+
+// Publish exports Snapshot as the expvar variable name. Like
+// expvar.Publish, it panics if name is already in use.
+func (collector *Collector) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return collector.Snapshot()
+	}))
+}
+
+func (collector *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		latency_desc, latency_min_desc, latency_max_desc, latency_mean_desc, latency_stddev_desc, rate_desc,
+		connections_desc, available_connections_desc, exceeded_pending_requests_desc, exceeded_write_bytes_desc,
+		connection_timeouts_desc, pending_request_timeouts_desc, request_timeouts_desc,
+		prepared_hits_desc, prepared_misses_desc, prepared_evictions_desc,
+	} {
+		ch <- desc
+	}
+}
+
+func (collector *Collector) Collect(ch chan<- prometheus.Metric) {
+	snapshot := collector.Snapshot()
+	names := make([]string, 0, len(snapshot))
+	for name := range snapshot {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		collect_session(ch, name, snapshot[name])
+	}
+}
+
+func collect_session(ch chan<- prometheus.Metric, name string, m cassandra.Metrics) {
+	gauge := func(desc *prometheus.Desc, value float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, append([]string{name}, labels...)...)
+	}
+	counter := func(desc *prometheus.Desc, value int64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, float64(value), name)
+	}
+
+	requests := m.Requests
+	for _, q := range []struct {
+		quantile string
+		micros   int64
+	}{
+		{"0.5", requests.Median},
+		{"0.75", requests.Percentile75th},
+		{"0.95", requests.Percentile95th},
+		{"0.98", requests.Percentile98th},
+		{"0.99", requests.Percentile99th},
+		{"0.999", requests.Percentile999th},
+	} {
+		gauge(latency_desc, seconds(q.micros), q.quantile)
+	}
+	gauge(latency_min_desc, seconds(requests.Min))
+	gauge(latency_max_desc, seconds(requests.Max))
+	gauge(latency_mean_desc, seconds(requests.Mean))
+	gauge(latency_stddev_desc, seconds(requests.Stddev))
+
+	gauge(rate_desc, requests.MeanRate, "mean")
+	gauge(rate_desc, requests.OneMinuteRate, "1m")
+	gauge(rate_desc, requests.FiveMinuteRate, "5m")
+	gauge(rate_desc, requests.FifteenMinuteRate, "15m")
+
+	gauge(connections_desc, float64(m.Stats.TotalConnections))
+	gauge(available_connections_desc, float64(m.Stats.AvailableConnections))
+	counter(exceeded_pending_requests_desc, m.Stats.ExceededPendingRequestsWaterMark)
+	counter(exceeded_write_bytes_desc, m.Stats.ExceededWriteBytesWaterMark)
+
+	counter(connection_timeouts_desc, m.Errors.ConnectionTimeouts)
+	counter(pending_request_timeouts_desc, m.Errors.PendingRequestTimeouts)
+	counter(request_timeouts_desc, m.Errors.RequestTimeouts)
+
+	counter(prepared_hits_desc, m.PreparedCache.Hits)
+	counter(prepared_misses_desc, m.PreparedCache.Misses)
+	counter(prepared_evictions_desc, m.PreparedCache.Evictions)
+}
+
+// seconds converts the driver's microsecond latencies to seconds.
+func seconds(micros int64) float64 {
+	return float64(micros) / 1e6
+}
@@ -0,0 +1,250 @@
+package cassandra
+
+// #include <stdlib.h>
+// #include <string.h>
+// #include <cassandra.h>
+import "C"
+import "strings"
+import "unsafe"
+
+// Values can be bound to named markers, either the column-derived names of
+// a prepared statement or the :name placeholders of an ad hoc statement:
+//
+//	statement := NewStatement("SELECT * FROM users WHERE id = :id", 1)
+//	err := statement.BindByName("id", id)
+//
+// Names are matched case-insensitively unless quoted in the query, as in
+// CQL itself.
+
+// BindByName binds v to every marker called name. It returns an error
+// matching ErrNameDoesNotExist if the statement has no such marker.
+func (statement *Statement) BindByName(name string, v interface{}) error {
+	if statement.prepared == nil && !statement.has_marker(name) {
+		return new_error_with_message(C.CASS_ERROR_LIB_NAME_DOES_NOT_EXIST, "name does not exist: "+name)
+	}
+
+	cs := C.CString(name)
+	defer C.free(unsafe.Pointer(cs))
+	return bind_value_by_name(statement, cs, v)
+}
+
+// BindNamed binds every entry of values with BindByName.
+func (statement *Statement) BindNamed(values map[string]interface{}) error {
+	for name, v := range values {
+		if err := statement.BindByName(name, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (statement *Statement) has_marker(name string) bool {
+	want := marker_name(name)
+	for _, marker := range statement.names {
+		if marker == want {
+			return true
+		}
+	}
+	return false
+}
+
+func bind_value_by_name(statement *Statement, name *C.char, v interface{}) error {
+	return bind_to(name_binder{statement, name}, v)
+}
+
+// name_binder binds to every marker called name.
+type name_binder struct {
+	statement *Statement
+	name      *C.char
+}
+
+func (b name_binder) bind_null() error {
+	if rc := C.cass_statement_bind_null_by_name(b.statement.cptr, b.name); rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+func (b name_binder) bind_unset() error {
+	return nil
+}
+
+func (b name_binder) bind_int8(v C.cass_int8_t) C.CassError {
+	return C.cass_statement_bind_int8_by_name(b.statement.cptr, b.name, v)
+}
+
+func (b name_binder) bind_int16(v C.cass_int16_t) C.CassError {
+	return C.cass_statement_bind_int16_by_name(b.statement.cptr, b.name, v)
+}
+
+func (b name_binder) bind_int32(v C.cass_int32_t) C.CassError {
+	return C.cass_statement_bind_int32_by_name(b.statement.cptr, b.name, v)
+}
+
+func (b name_binder) bind_int64(v C.cass_int64_t) C.CassError {
+	return C.cass_statement_bind_int64_by_name(b.statement.cptr, b.name, v)
+}
+
+func (b name_binder) bind_uint32(v C.cass_uint32_t) C.CassError {
+	return C.cass_statement_bind_uint32_by_name(b.statement.cptr, b.name, v)
+}
+
+func (b name_binder) bind_float(v C.cass_float_t) C.CassError {
+	return C.cass_statement_bind_float_by_name(b.statement.cptr, b.name, v)
+}
+
+func (b name_binder) bind_double(v C.cass_double_t) C.CassError {
+	return C.cass_statement_bind_double_by_name(b.statement.cptr, b.name, v)
+}
+
+func (b name_binder) bind_bool(v C.cass_bool_t) C.CassError {
+	return C.cass_statement_bind_bool_by_name(b.statement.cptr, b.name, v)
+}
+
+func (b name_binder) bind_string(v string) C.CassError {
+	cs := C.CString(v)
+	defer C.free(unsafe.Pointer(cs))
+	return C.cass_statement_bind_string_by_name_n(b.statement.cptr, b.name, C.strlen(b.name), cs, C.size_t(len(v)))
+}
+
+func (b name_binder) bind_bytes(v []byte) C.CassError {
+	return C.cass_statement_bind_bytes_by_name(b.statement.cptr, b.name, bytes_ptr(v), C.size_t(len(v)))
+}
+
+func (b name_binder) bind_uuid(v C.struct_CassUuid_) C.CassError {
+	return C.cass_statement_bind_uuid_by_name(b.statement.cptr, b.name, v)
+}
+
+func (b name_binder) bind_inet(v C.CassInet) C.CassError {
+	return C.cass_statement_bind_inet_by_name(b.statement.cptr, b.name, v)
+}
+
+func (b name_binder) bind_decimal(varint []byte, scale C.cass_int32_t) C.CassError {
+	return C.cass_statement_bind_decimal_by_name(b.statement.cptr, b.name, bytes_ptr(varint), C.size_t(len(varint)), scale)
+}
+
+func (b name_binder) bind_duration(months, days C.cass_int32_t, nanos C.cass_int64_t) C.CassError {
+	return C.cass_statement_bind_duration_by_name(b.statement.cptr, b.name, months, days, nanos)
+}
+
+func (b name_binder) bind_collection(v interface{}) error {
+	return bind_collection_by_name(b.statement, b.name, v)
+}
+
+// marker_name normalizes a marker name the way Cassandra does: quoted
+// names keep their case, unquoted names are lower-cased.
+func marker_name(name string) string {
+	if len(name) >= 2 && name[0] == '"' && name[len(name)-1] == '"' {
+		return strings.ReplaceAll(name[1:len(name)-1], `""`, `"`)
+	}
+	b := []byte(name)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+// parse_markers returns the names of the :name markers in query, skipping
+// string literals, $$ literals, quoted identifiers and comments. A double
+// colon is not a marker.
+func parse_markers(query string) []string {
+	var names []string
+
+	tokens := cql_tokens(query)
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i] != ":" {
+			continue
+		}
+		if tokens[i+1] == ":" {
+			i++
+			continue
+		}
+		if is_name_token(tokens[i+1]) {
+			names = append(names, marker_name(tokens[i+1]))
+			i++
+		}
+	}
+
+	return names
+}
+
+// is_name_token reports whether token is an identifier, quoted or not.
+func is_name_token(token string) bool {
+	if token[0] == '"' {
+		return len(token) >= 2 && token[len(token)-1] == '"'
+	}
+	return is_identifier_byte(token[0], true)
+}
+
+func is_identifier_byte(c byte, first bool) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		return true
+	case '0' <= c && c <= '9', c == '_':
+		return !first
+	}
+	return false
+}
+
+// cql_tokens splits query into tokens, dropping whitespace and comments.
+// String literals, $$ literals and quoted identifiers are one token each,
+// quotes included, as is each run of identifier characters. Anything else
+// is a token of one byte.
+func cql_tokens(query string) []string {
+	var tokens []string
+
+	for i := 0; i < len(query); i++ {
+		start := i
+		switch c := query[i]; {
+
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			continue
+
+		case c == '\'' || c == '"':
+			for i++; i < len(query); i++ {
+				if query[i] == c {
+					if i+1 < len(query) && query[i+1] == c {
+						i++
+						continue
+					}
+					break
+				}
+			}
+
+		case c == '$' && i+1 < len(query) && query[i+1] == '$':
+			if end := strings.Index(query[i+2:], "$$"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(query) - 1
+			}
+
+		case c == '-' && i+1 < len(query) && query[i+1] == '-',
+			c == '/' && i+1 < len(query) && query[i+1] == '/':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+			continue
+
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			for i += 2; i+1 < len(query) && !(query[i] == '*' && query[i+1] == '/'); i++ {
+			}
+			i++
+			continue
+
+		case is_identifier_byte(c, false):
+			for i+1 < len(query) && is_identifier_byte(query[i+1], false) {
+				i++
+			}
+		}
+
+		end := i + 1
+		if end > len(query) {
+			end = len(query)
+		}
+		tokens = append(tokens, query[start:end])
+	}
+
+	return tokens
+}
@@ -0,0 +1,33 @@
+package cassandra
+
+import "reflect"
+import "testing"
+
+func TestParseMarkers(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"SELECT * FROM users WHERE id = :id AND Name = :Name", []string{"id", "name"}},
+		{`UPDATE t SET a = :"MixedCase" WHERE k = :"a""b"`, []string{"MixedCase", `a"b`}},
+		{`SELECT ":fake" FROM t WHERE k = :k`, []string{"k"}},
+		{"SELECT * FROM t WHERE s = ':fake' AND k = :k", []string{"k"}},
+		{"SELECT * FROM t WHERE s = 'it''s :fake' AND k = :k", []string{"k"}},
+		{"SELECT * FROM t -- :fake\nWHERE k = :k", []string{"k"}},
+		{"SELECT * FROM t // :fake\nWHERE k = :k", []string{"k"}},
+		{"SELECT * FROM t /* :fake\n:fake */ WHERE k = :k", []string{"k"}},
+		{"SELECT * FROM t WHERE s = $$:fake 'x'$$ AND k = :k", []string{"k"}},
+		{"SELECT * FROM t WHERE s = $$:fake", nil},
+		{"SELECT a::b FROM t WHERE k = :k", []string{"k"}},
+		{"SELECT * FROM t WHERE k = ?", nil},
+		{"SELECT * FROM t WHERE k = :1", nil},
+		{"SELECT * FROM t WHERE k = :", nil},
+		{`SELECT * FROM t WHERE k = :"open`, nil},
+	}
+
+	for _, test := range tests {
+		if got := parse_markers(test.query); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parse_markers(%q) = %q, want %q", test.query, got, test.want)
+		}
+	}
+}
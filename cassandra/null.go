@@ -0,0 +1,133 @@
+package cassandra
+
+// #include <cassandra.h>
+import "C"
+import "math/big"
+import "reflect"
+import "time"
+
+// NULL columns can be told apart from zero values by scanning into a
+// pointer to a pointer, which is set to nil for NULL, or into one of the
+// Null wrappers:
+//
+//	var name *string
+//	var age NullInt32
+//	result.Scan(&name, &age)
+//
+// Any other target is set to its zero value for NULL, whatever its type.
+//
+// Bind goes the other way: nil pointers and invalid wrappers bind NULL,
+// other pointers and wrappers bind what they hold.
+
+var (
+	_ null_scanner = (*NullString)(nil)
+	_ null_valuer  = NullString{}
+	_ null_valuer  = (*NullString)(nil)
+)
+
+// Null holds a value that may be NULL. Valid is false for NULL.
+type Null[T any] struct {
+	Value T
+	Valid bool
+}
+
+type NullString = Null[string]
+type NullInt32 = Null[int32]
+type NullInt64 = Null[int64]
+type NullFloat64 = Null[float64]
+type NullBool = Null[bool]
+type NullTime = Null[time.Time]
+type NullUuid = Null[Uuid]
+
+// null_scanner is implemented by *Null[T], and null_valuer by both Null[T]
+// and *Null[T], so that wrappers bind whether passed by value or pointer.
+type null_scanner interface {
+	scan_null(value *C.CassValue) error
+}
+
+type null_valuer interface {
+	bind_arg() interface{}
+}
+
+func (n *Null[T]) scan_null(value *C.CassValue) error {
+	if C.cass_value_is_null(value) != 0 {
+		*n = Null[T]{}
+		return nil
+	}
+	if err := scan_value(value, &n.Value); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+func (n Null[T]) bind_arg() interface{} {
+	if !n.Valid {
+		return nil
+	}
+	return n.Value
+}
+
+// UnsetValue is the type of Unset.
+type UnsetValue struct{}
+
+// Unset leaves a bind marker without a value, so that an insert or update
+// does not touch the column instead of writing a tombstone. It needs
+// protocol v4 or later; older servers reject the statement.
+//
+// The driver cannot unset a single marker, so binding Unset to a marker
+// that already has a value leaves that value in place. To reuse a
+// statement, call ResetParameters before binding the new values.
+var Unset UnsetValue
+
+// IsNull reports whether column index of the current row is NULL. It
+// returns false if Next has not been called yet.
+func (result *Result) IsNull(index uint64) bool {
+	if result.iter == nil {
+		return false
+	}
+	row := C.cass_iterator_get_row(result.iter)
+	value := C.cass_row_get_column(row, C.size_t(index))
+	return value == nil || C.cass_value_is_null(value) != 0
+}
+
+// bind_arg replaces v with what Bind should send for it: nil for nil
+// pointers and invalid Null wrappers, and the value held by other pointers
+// and wrappers. *big.Int and *big.Float are bound as they are.
+func bind_arg(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil
+	}
+	if n, ok := v.(null_valuer); ok {
+		return bind_arg(n.bind_arg())
+	}
+	if rv.Kind() != reflect.Ptr {
+		return v
+	}
+	switch v.(type) {
+	case *big.Int, *big.Float:
+		return v
+	}
+	return bind_arg(rv.Elem().Interface())
+}
+
+// scan_pointer scans into rv, a **T, setting *rv to nil for NULL and
+// allocating a T if needed otherwise.
+func scan_pointer(value *C.CassValue, rv reflect.Value) error {
+	target := rv.Elem()
+	if C.cass_value_is_null(value) != 0 {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	elem := target
+	if elem.IsNil() {
+		elem = reflect.New(target.Type().Elem())
+	}
+	if err := scan_value(value, elem.Interface()); err != nil {
+		return err
+	}
+	target.Set(elem)
+	return nil
+}
@@ -0,0 +1,94 @@
+package cassandra
+
+import "errors"
+import "math/big"
+import "testing"
+
+func TestBindArg(t *testing.T) {
+	s := "text"
+	n := big.NewInt(42)
+	tests := []struct {
+		name string
+		v    interface{}
+		want interface{}
+	}{
+		{"valid wrapper", NullString{Value: "a", Valid: true}, "a"},
+		{"invalid wrapper", NullString{Value: "a"}, nil},
+		{"wrapper pointer", &NullInt64{Value: 5, Valid: true}, int64(5)},
+		{"nil wrapper pointer", (*NullInt64)(nil), nil},
+		{"pointer", &s, "text"},
+		{"nil pointer", (*string)(nil), nil},
+		{"big.Int", n, n},
+		{"nil big.Int", (*big.Int)(nil), nil},
+		{"unset", Unset, Unset},
+	}
+
+	for _, test := range tests {
+		if got := bind_arg(test.v); got != test.want {
+			t.Errorf("%s: bind_arg(%#v) = %#v, want %#v", test.name, test.v, got, test.want)
+		}
+	}
+}
+
+func TestBindNullWrappers(t *testing.T) {
+	statement := NewStatement("INSERT INTO users (name, age, email) VALUES (?, ?, ?)", 3)
+	defer statement.Close()
+
+	err := statement.Bind(NullString{Value: "bob", Valid: true}, NullInt32{}, Unset)
+	if err != nil {
+		t.Fatalf("Bind of wrappers by value: %v", err)
+	}
+	if err := statement.Bind(&NullString{Value: "bob", Valid: true}); err != nil {
+		t.Fatalf("Bind of a wrapper pointer: %v", err)
+	}
+	if err := statement.Bind(struct{}{}); err == nil {
+		t.Fatal("Bind of an unsupported type succeeded")
+	}
+}
+
+func TestScanNilTarget(t *testing.T) {
+	for _, v := range []interface{}{(*big.Int)(nil), (*big.Float)(nil), (*NullString)(nil)} {
+		err := scan_value(nil, v)
+		var mismatch *TypeMismatchError
+		if !errors.As(err, &mismatch) {
+			t.Errorf("scan_value into %T(nil) = %v, want a TypeMismatchError", v, err)
+		}
+	}
+}
+
+func TestIsNullBeforeNext(t *testing.T) {
+	if new(Result).IsNull(0) {
+		t.Error("IsNull before Next reported NULL")
+	}
+}
+
+func TestScanBeforeNext(t *testing.T) {
+	var id int32
+	var row struct{ ID int32 }
+	tests := []struct {
+		name string
+		scan func() error
+	}{
+		{"Result.Scan", func() error { return new(Result).Scan(&id) }},
+		{"Result.MapScan", func() error { return new(Result).MapScan(map[string]interface{}{}) }},
+		{"Result.ScanStruct", func() error { return new(Result).ScanStruct(&row) }},
+		{"Iter.Scan", func() error { return new(Iter).Scan(&id) }},
+		{"Iter.ScanStruct", func() error { return new(Iter).ScanStruct(&row) }},
+	}
+
+	for _, test := range tests {
+		if err := test.scan(); err == nil {
+			t.Errorf("%s before Next succeeded", test.name)
+		}
+	}
+}
+
+func TestCopyEmptyBytes(t *testing.T) {
+	if got := copy_bytes(nil, nil, 0); got == nil || len(got) != 0 {
+		t.Errorf("copy_bytes of an empty value into nil = %#v, want []byte{}", got)
+	}
+	reused := make([]byte, 4)
+	if got := copy_bytes(reused, nil, 0); got == nil || len(got) != 0 {
+		t.Errorf("copy_bytes of an empty value into a buffer = %#v, want []byte{}", got)
+	}
+}
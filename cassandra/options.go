@@ -0,0 +1,125 @@
+package cassandra
+
+// #include <stdlib.h>
+// #include <cassandra.h>
+import "C"
+import "time"
+import "unsafe"
+
+func (statement *Statement) SetConsistency(consistency Consistency) error {
+	rc := C.cass_statement_set_consistency(statement.cptr, C.CassConsistency(consistency))
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+// SetSerialConsistency sets the consistency of the Paxos phase of
+// lightweight transactions, CASS_CONSISTENCY_SERIAL or
+// CASS_CONSISTENCY_LOCAL_SERIAL.
+func (statement *Statement) SetSerialConsistency(consistency Consistency) error {
+	rc := C.cass_statement_set_serial_consistency(statement.cptr, C.CassConsistency(consistency))
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+// SetTimestamp sets the write time of the statement, in microseconds since
+// the Unix epoch.
+func (statement *Statement) SetTimestamp(timestamp int64) error {
+	rc := C.cass_statement_set_timestamp(statement.cptr, C.cass_int64_t(timestamp))
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+// SetRequestTimeout overrides the cluster's request timeout for this
+// statement. It is rounded up to whole milliseconds.
+func (statement *Statement) SetRequestTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return bad_params("request timeout must be positive")
+	}
+	rc := C.cass_statement_set_request_timeout(statement.cptr, C.cass_uint64_t(timeout_ms(timeout)))
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	statement.timeout = timeout
+	return nil
+}
+
+// SetKeyspace sets the keyspace the statement is routed by. It does not
+// change the keyspace of unqualified table names in the query.
+func (statement *Statement) SetKeyspace(keyspace string) error {
+	cs := C.CString(keyspace)
+	defer C.free(unsafe.Pointer(cs))
+	rc := C.cass_statement_set_keyspace(statement.cptr, cs)
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+// SetConsistency sets the default consistency of statements.
+func (cluster *Cluster) SetConsistency(consistency Consistency) error {
+	rc := C.cass_cluster_set_consistency(cluster.cptr, C.CassConsistency(consistency))
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+// SetSerialConsistency sets the default serial consistency of statements.
+func (cluster *Cluster) SetSerialConsistency(consistency Consistency) error {
+	rc := C.cass_cluster_set_serial_consistency(cluster.cptr, C.CassConsistency(consistency))
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+// SetRequestTimeout sets the default request timeout of statements. It is
+// rounded up to whole milliseconds.
+func (cluster *Cluster) SetRequestTimeout(timeout time.Duration) error {
+	ms := timeout_ms(timeout)
+	if timeout <= 0 || ms > uint64(^C.unsigned(0)) {
+		return bad_params("request timeout out of range")
+	}
+	C.cass_cluster_set_request_timeout(cluster.cptr, C.unsigned(ms))
+	return nil
+}
+
+// SetClientTimestamps chooses where statements without a timestamp get
+// one: from a monotonic clock in the client when enabled, or from the
+// server, which is the default.
+func (cluster *Cluster) SetClientTimestamps(enabled bool) {
+	var gen *C.struct_CassTimestampGen_
+	if enabled {
+		gen = C.cass_timestamp_gen_monotonic_new()
+	} else {
+		gen = C.cass_timestamp_gen_server_side_new()
+	}
+	C.cass_cluster_set_timestamp_gen(cluster.cptr, gen)
+	C.cass_timestamp_gen_free(gen)
+}
+
+// SetIdempotent sets whether statements are idempotent unless they say
+// otherwise with Statement.SetIdempotent. It applies to sessions connected
+// after the call. Go retry policies see the default for every statement;
+// the driver's own policies only for statements the session creates, in
+// Query and database/sql.
+func (cluster *Cluster) SetIdempotent(idempotent bool) {
+	cluster.idempotent = idempotent
+}
+
+// SetPreparedCacheSize sets how many statements Session.Query keeps
+// prepared, 1000 by default. It applies to sessions connected after the
+// call.
+func (cluster *Cluster) SetPreparedCacheSize(size int) error {
+	if size <= 0 {
+		return bad_params("prepared cache size must be positive")
+	}
+	cluster.cache_size = size
+	return nil
+}
@@ -0,0 +1,224 @@
+package cassandra
+
+// #include <cassandra.h>
+import "C"
+import "context"
+import "encoding/base64"
+import "unsafe"
+
+// PagingToken is the opaque position of a paged query. It can be handed to
+// clients as text and passed back to Statement.SetPagingToken to resume the
+// query where it stopped.
+type PagingToken []byte
+
+func (token PagingToken) String() string {
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+func (token PagingToken) MarshalText() ([]byte, error) {
+	return []byte(token.String()), nil
+}
+
+func (token *PagingToken) UnmarshalText(text []byte) error {
+	b, err := base64.RawURLEncoding.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	*token = b
+	return nil
+}
+
+// SetPagingSize sets the number of rows returned per page. A size of zero
+// or less disables paging.
+func (statement *Statement) SetPagingSize(size int) error {
+	rc := C.cass_statement_set_paging_size(statement.cptr, C.int(size))
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+// SetPagingState makes the next execution of statement return the page
+// that follows result.
+func (statement *Statement) SetPagingState(result *Result) error {
+	rc := C.cass_statement_set_paging_state(statement.cptr, result.cptr)
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+// SetPagingToken makes the next execution of statement resume from token.
+func (statement *Statement) SetPagingToken(token PagingToken) error {
+	rc := C.cass_statement_set_paging_state_token(statement.cptr,
+		(*C.char)(unsafe.Pointer(bytes_ptr(token))), C.size_t(len(token)))
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	return nil
+}
+
+// PagingToken returns the position after this page, or nil if this is the
+// last page.
+func (result *Result) PagingToken() (PagingToken, error) {
+	if !result.HasMorePages() {
+		return nil, nil
+	}
+
+	var token *C.char
+	var size C.size_t
+	rc := C.cass_result_paging_state_token(result.cptr, &token, &size)
+	if rc != C.CASS_OK {
+		return nil, new_error(rc)
+	}
+	return PagingToken(C.GoBytes(unsafe.Pointer(token), C.int(size))), nil
+}
+
+// Iter walks every row of a paged query. While the caller consumes one page
+// the next is already being fetched.
+type Iter struct {
+	ctx       context.Context
+	session   *Session
+	statement *Statement
+	result    *Result
+	next      *Future
+	restore   func()
+	err       error
+}
+
+// Iter executes statement and returns an iterator over all of its pages.
+// Use Statement.SetPagingSize to control the page size. The statement is
+// modified as pages are fetched and must not be shared until the iterator
+// is closed.
+func (session *Session) Iter(statement *Statement) *Iter {
+	return session.IterContext(context.Background(), statement)
+}
+
+// IterContext is Iter with a context that bounds the whole iteration: once
+// ctx is done, Next returns false and Err returns ctx.Err(). A deadline on
+// ctx is applied to every page request as its timeout, as in
+// ExecuteContext.
+func (session *Session) IterContext(ctx context.Context, statement *Statement) *Iter {
+	iter := &Iter{
+		ctx:       ctx,
+		session:   session,
+		statement: statement,
+	}
+	iter.execute()
+	return iter
+}
+
+// execute requests the next page.
+func (iter *Iter) execute() {
+	restore, err := apply_deadline(iter.ctx, iter.statement)
+	if err != nil {
+		iter.err = err
+		return
+	}
+	iter.restore = restore
+	iter.next = iter.session.execute_context(iter.ctx, iter.statement)
+}
+
+// abandon frees the page being fetched, and puts back the statement's
+// timeout, once it arrives.
+func (iter *Iter) abandon() {
+	restore := iter.restore
+	iter.next.OnComplete(func(future *Future) {
+		restore()
+		future.Close()
+	})
+	iter.next = nil
+}
+
+// Next advances to the next row, waiting for the next page if the current
+// one is used up. It returns false at the end of the results or on error;
+// check Err to tell the two apart.
+func (iter *Iter) Next() bool {
+	for iter.err == nil {
+		if iter.result != nil && iter.result.Next() {
+			return true
+		}
+		if iter.next == nil {
+			return false
+		}
+		iter.fetch()
+	}
+	return false
+}
+
+func (iter *Iter) fetch() {
+	future := iter.next
+
+	select {
+	case <-future.Done():
+	case <-iter.ctx.Done():
+		iter.abandon()
+		iter.err = iter.ctx.Err()
+		return
+	}
+	iter.next = nil
+	iter.restore()
+
+	if err := future.Err(); err != nil {
+		future.Close()
+		iter.err = err
+		return
+	}
+	result := future.Result()
+	future.Close()
+
+	if iter.result != nil {
+		iter.result.Close()
+	}
+	iter.result = result
+
+	if result.HasMorePages() {
+		if err := iter.statement.SetPagingState(result); err != nil {
+			iter.err = err
+			return
+		}
+		iter.execute()
+	}
+}
+
+// Scan copies the columns of the current row into args, like Result.Scan.
+func (iter *Iter) Scan(args ...interface{}) error {
+	return iter.result.Scan(args...)
+}
+
+// ScanStruct copies the current row into a struct, like Result.ScanStruct.
+func (iter *Iter) ScanStruct(v interface{}) error {
+	return iter.result.ScanStruct(v)
+}
+
+// Result returns the page the iterator is currently reading.
+func (iter *Iter) Result() *Result {
+	return iter.result
+}
+
+// PagingToken returns the position after the current page, or nil if it is
+// the last one. Rows of the current page not yet read are not included
+// when resuming from it.
+func (iter *Iter) PagingToken() (PagingToken, error) {
+	if iter.result == nil {
+		return nil, nil
+	}
+	return iter.result.PagingToken()
+}
+
+func (iter *Iter) Err() error {
+	return iter.err
+}
+
+// Close releases the current page. A page still being fetched is released
+// once it arrives.
+func (iter *Iter) Close() error {
+	if iter.next != nil {
+		iter.abandon()
+	}
+	if iter.result != nil {
+		iter.result.Close()
+		iter.result = nil
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package cassandra
+
+// #include <cassandra.h>
+import "C"
+import "context"
+
+type WriteType int
+
+const (
+	CASS_WRITE_TYPE_UNKNOWN        WriteType = C.CASS_WRITE_TYPE_UNKNOWN
+	CASS_WRITE_TYPE_SIMPLE         WriteType = C.CASS_WRITE_TYPE_SIMPLE
+	CASS_WRITE_TYPE_BATCH          WriteType = C.CASS_WRITE_TYPE_BATCH
+	CASS_WRITE_TYPE_UNLOGGED_BATCH WriteType = C.CASS_WRITE_TYPE_UNLOGGED_BATCH
+	CASS_WRITE_TYPE_COUNTER        WriteType = C.CASS_WRITE_TYPE_COUNTER
+	CASS_WRITE_TYPE_BATCH_LOG      WriteType = C.CASS_WRITE_TYPE_BATCH_LOG
+	CASS_WRITE_TYPE_CAS            WriteType = C.CASS_WRITE_TYPE_CAS
+	CASS_WRITE_TYPE_VIEW           WriteType = C.CASS_WRITE_TYPE_VIEW
+	CASS_WRITE_TYPE_CDC            WriteType = C.CASS_WRITE_TYPE_CDC
+)
+
+func (write_type WriteType) String() string {
+	return C.GoString(C.cass_write_type_string(C.CassWriteType(write_type)))
+}
+
+// ErrorResult describes why a request failed. The fields after Err are only
+// set for the server errors that carry them: Consistency, Received and
+// Required for timeouts and ErrUnavailable, DataPresent for read timeouts
+// and WriteType for write timeouts.
+type ErrorResult struct {
+	Err *Error
+
+	Consistency Consistency
+	Received    int
+	Required    int
+	NumFailures int
+	DataPresent bool
+	WriteType   WriteType
+}
+
+// ErrorResult waits for the future and describes its error, or returns nil
+// if the request succeeded.
+func (future *Future) ErrorResult() *ErrorResult {
+	future.wait()
+	rc := C.cass_future_error_code(future.cptr)
+	if rc == C.CASS_OK {
+		return nil
+	}
+
+	result := &ErrorResult{
+		Err:         new_error_with_message(rc, future.ErrorMessage()),
+		Consistency: CASS_CONSISTENCY_UNKNOWN,
+		WriteType:   CASS_WRITE_TYPE_UNKNOWN,
+	}
+
+	cresult := C.cass_future_get_error_result(future.cptr)
+	if cresult == nil {
+		return result
+	}
+	defer C.cass_error_result_free(cresult)
+
+	result.Consistency = Consistency(C.cass_error_result_consistency(cresult))
+	result.Received = int(C.cass_error_result_responses_received(cresult))
+	result.Required = int(C.cass_error_result_responses_required(cresult))
+	result.NumFailures = int(C.cass_error_result_num_failures(cresult))
+	result.DataPresent = C.cass_error_result_data_present(cresult) != 0
+	result.WriteType = WriteType(C.cass_error_result_write_type(cresult))
+	return result
+}
+
+// RetryDecision is what a RetryPolicy wants done with a failed request.
+type RetryDecision struct {
+	Retry bool
+
+	// Consistency is the level to retry at, or CASS_CONSISTENCY_UNKNOWN to
+	// keep the statement's current level.
+	Consistency Consistency
+}
+
+var (
+	// RETHROW gives up and returns the error.
+	RETHROW = RetryDecision{Retry: false, Consistency: CASS_CONSISTENCY_UNKNOWN}
+
+	// RETRY tries again at the same consistency.
+	RETRY = RetryDecision{Retry: true, Consistency: CASS_CONSISTENCY_UNKNOWN}
+)
+
+// RetryAt tries again at consistency. The statement keeps the new level.
+func RetryAt(consistency Consistency) RetryDecision {
+	return RetryDecision{Retry: true, Consistency: consistency}
+}
+
+// RetryPolicy decides whether a failed request is retried. Policies written
+// in Go run once the driver has given up on a request, and only for
+// statements marked idempotent with SetIdempotent. Retry is called with the
+// number of the attempt that failed, starting at 1, on a goroutine of its
+// own, so it may block to back off before retrying.
+//
+// The driver's own policies, made by NewDefaultRetryPolicy and friends,
+// are DriverRetryPolicy values; they run inside the driver instead.
+type RetryPolicy interface {
+	Retry(statement *Statement, result *ErrorResult, attempt int) RetryDecision
+}
+
+// RetryPolicyFunc adapts a function to a RetryPolicy.
+type RetryPolicyFunc func(statement *Statement, result *ErrorResult, attempt int) RetryDecision
+
+func (fn RetryPolicyFunc) Retry(statement *Statement, result *ErrorResult, attempt int) RetryDecision {
+	return fn(statement, result, attempt)
+}
+
+type DriverRetryPolicy struct {
+	cptr *C.struct_CassRetryPolicy_
+}
+
+func new_driver_retry_policy(cptr *C.struct_CassRetryPolicy_) *DriverRetryPolicy {
+	policy := &DriverRetryPolicy{cptr: cptr}
+	track(policy)
+	return policy
+}
+
+// NewDefaultRetryPolicy retries read and write timeouts once if enough
+// replicas answered, and tries the next host once when unavailable.
+func NewDefaultRetryPolicy() *DriverRetryPolicy {
+	return new_driver_retry_policy(C.cass_retry_policy_default_new())
+}
+
+// NewDowngradingConsistencyRetryPolicy retries at a lower consistency when
+// not enough replicas are available. Writes may then be applied to fewer
+// replicas than asked for.
+func NewDowngradingConsistencyRetryPolicy() *DriverRetryPolicy {
+	return new_driver_retry_policy(C.cass_retry_policy_downgrading_consistency_new())
+}
+
+// NewFallthroughRetryPolicy never retries.
+func NewFallthroughRetryPolicy() *DriverRetryPolicy {
+	return new_driver_retry_policy(C.cass_retry_policy_fallthrough_new())
+}
+
+// NewLoggingRetryPolicy logs the decisions of child to the driver's log. A
+// nil child logs the decisions of NewDefaultRetryPolicy.
+func NewLoggingRetryPolicy(child *DriverRetryPolicy) *DriverRetryPolicy {
+	if child == nil {
+		// The logging policy keeps its own reference to the child.
+		child = NewDefaultRetryPolicy()
+		defer child.Close()
+	}
+	return new_driver_retry_policy(C.cass_retry_policy_logging_new(child.cptr))
+}
+
+// Retry always returns RETHROW: the driver has already applied the policy
+// by the time Go sees the error.
+func (policy *DriverRetryPolicy) Retry(statement *Statement, result *ErrorResult, attempt int) RetryDecision {
+	return RETHROW
+}
+
+func (policy *DriverRetryPolicy) Close() error {
+	if policy.cptr == nil {
+		return nil
+	}
+	C.cass_retry_policy_free(policy.cptr)
+	policy.cptr = nil
+	untrack(policy)
+	return nil
+}
+
+func (policy *DriverRetryPolicy) Finalize() {
+	policy.Close()
+}
+
+// SetRetryPolicy sets the policy used by sessions connected after the call.
+// The cluster keeps its own reference to a DriverRetryPolicy, which may be
+// closed afterwards.
+func (cluster *Cluster) SetRetryPolicy(policy RetryPolicy) {
+	if driver, ok := policy.(*DriverRetryPolicy); ok {
+		C.cass_cluster_set_retry_policy(cluster.cptr, driver.cptr)
+	}
+	cluster.retry = policy
+}
+
+// SetRetryPolicy overrides the session's policy for this statement. A nil
+// policy restores the session's.
+func (statement *Statement) SetRetryPolicy(policy RetryPolicy) error {
+	var cpolicy *C.struct_CassRetryPolicy_
+	if driver, ok := policy.(*DriverRetryPolicy); ok {
+		cpolicy = driver.cptr
+	}
+	rc := C.cass_statement_set_retry_policy(statement.cptr, cpolicy)
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	statement.retry = policy
+	return nil
+}
+
+// SetIdempotent marks the statement as safe to run more than once, which
+// lets the driver and Go retry policies retry it.
+func (statement *Statement) SetIdempotent(idempotent bool) error {
+	rc := C.cass_statement_set_is_idempotent(statement.cptr, cass_bool(idempotent))
+	if rc != C.CASS_OK {
+		return new_error(rc)
+	}
+	statement.idempotent = idempotent
+	statement.idempotent_set = true
+	return nil
+}
+
+// is_idempotent applies the session's default to statements that have not
+// been marked either way.
+func (session *Session) is_idempotent(statement *Statement) bool {
+	if statement.idempotent_set {
+		return statement.idempotent
+	}
+	return session.idempotent
+}
+
+// mark_idempotent tells the driver about the session's default, for
+// statements the session creates itself.
+func (session *Session) mark_idempotent(statement *Statement) {
+	if session.idempotent {
+		C.cass_statement_set_is_idempotent(statement.cptr, C.cass_true)
+	}
+}
+
+// retry_policy returns the Go policy that applies to statement, if any.
+func (session *Session) retry_policy(statement *Statement) RetryPolicy {
+	if !session.is_idempotent(statement) {
+		return nil
+	}
+	policy := statement.retry
+	if policy == nil {
+		policy = session.retry
+	}
+	if _, ok := policy.(*DriverRetryPolicy); ok {
+		return nil
+	}
+	return policy
+}
+
+// execute_retry runs statement until it succeeds, policy gives up or ctx is
+// done. It returns at once; the future completes with the last attempt.
+func (session *Session) execute_retry(ctx context.Context, statement *Statement, policy RetryPolicy) *Future {
+	future := &Future{chained: true, watching: true, table: session.qualify(statement.table)}
+	track(future)
+	session.retry_attempt(ctx, future, statement, policy, 1)
+	return future
+}
+
+// retry_attempt sends attempt number attempt and, once it completes, either
+// sends the next one or hands the attempt over to future.
+func (session *Session) retry_attempt(ctx context.Context, future *Future, statement *Statement, policy RetryPolicy, attempt int) {
+	session.execute(statement).OnComplete(func(last *Future) {
+		// Policies may block, so they do not run on the driver's IO thread.
+		go func() {
+			if ctx.Err() == nil && !future.is_closed() && retry(statement, policy, last, attempt) {
+				last.Close()
+				session.retry_attempt(ctx, future, statement, policy, attempt+1)
+				return
+			}
+			future.adopt(last)
+		}()
+	})
+}
+
+// retry asks policy about the failed attempt and applies the consistency it
+// asks for. It reports false if the attempt succeeded.
+func retry(statement *Statement, policy RetryPolicy, last *Future, attempt int) bool {
+	result := last.ErrorResult()
+	if result == nil {
+		return false
+	}
+	decision := policy.Retry(statement, result, attempt)
+	if !decision.Retry {
+		return false
+	}
+	if decision.Consistency != CASS_CONSISTENCY_UNKNOWN {
+		rc := C.cass_statement_set_consistency(statement.cptr, C.CassConsistency(decision.Consistency))
+		return rc == C.CASS_OK
+	}
+	return true
+}
@@ -0,0 +1,11 @@
+package cassandra
+
+import "testing"
+
+func TestLoggingRetryPolicyNilChild(t *testing.T) {
+	policy := NewLoggingRetryPolicy(nil)
+	if policy == nil {
+		t.Fatal("NewLoggingRetryPolicy(nil) returned nil")
+	}
+	policy.Close()
+}
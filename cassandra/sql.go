@@ -0,0 +1,561 @@
+package cassandra
+
+// #include <stdlib.h>
+// #include <cassandra.h>
+import "C"
+import "context"
+import "database/sql"
+import "database/sql/driver"
+import "io"
+import "math"
+import "math/big"
+import "net"
+import "net/url"
+import "strconv"
+import "strings"
+import "sync"
+import "time"
+import "unsafe"
+
+// The package registers itself with database/sql as "cassandra":
+//
+//	db, err := sql.Open("cassandra", "cassandra://host1,host2:9042/keyspace?consistency=local_quorum")
+//
+// All connections opened from one sql.DB share a single Session, which
+// already pools connections to every host; sql.DB.Close closes it.
+// Transactions are not supported.
+
+func init() {
+	sql.Register("cassandra", &sql_driver{})
+}
+
+// DSN is a parsed database/sql data source name.
+type DSN struct {
+	Hosts    []string
+	Port     int
+	Keyspace string
+
+	// Consistency applies to every statement run through database/sql.
+	// CASS_CONSISTENCY_UNKNOWN leaves the driver's default in place.
+	Consistency Consistency
+}
+
+// ParseDSN parses a data source name of the form
+//
+//	cassandra://host1,host2:9042/keyspace?consistency=quorum
+//
+// The scheme, port, keyspace and consistency are optional, so "host1,host2"
+// on its own is a valid DSN.
+func ParseDSN(dsn string) (*DSN, error) {
+	if !strings.Contains(dsn, "://") {
+		dsn = "cassandra://" + dsn
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, bad_params("invalid DSN: " + err.Error())
+	}
+	if u.Scheme != "cassandra" {
+		return nil, bad_params("invalid DSN scheme: " + u.Scheme)
+	}
+
+	config := &DSN{Consistency: CASS_CONSISTENCY_UNKNOWN}
+	for _, host := range strings.Split(u.Hostname(), ",") {
+		if host != "" {
+			config.Hosts = append(config.Hosts, host)
+		}
+	}
+	if len(config.Hosts) == 0 {
+		return nil, bad_params("DSN has no hosts")
+	}
+	if port := u.Port(); port != "" {
+		config.Port, err = strconv.Atoi(port)
+		if err != nil {
+			return nil, bad_params("invalid DSN port: " + port)
+		}
+	}
+	config.Keyspace = strings.Trim(u.Path, "/")
+
+	for key, values := range u.Query() {
+		switch key {
+		case "consistency":
+			config.Consistency, err = ParseConsistency(values[len(values)-1])
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, bad_params("unknown DSN parameter: " + key)
+		}
+	}
+
+	return config, nil
+}
+
+type sql_driver struct{}
+
+func (d *sql_driver) Open(dsn string) (driver.Conn, error) {
+	connector, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := connector.Connect(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	// Nothing else will ever close this connector, so the connection owns it.
+	conn.(*sql_conn).owner = connector.(*sql_connector)
+	return conn, nil
+}
+
+func (d *sql_driver) OpenConnector(dsn string) (driver.Connector, error) {
+	config, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &sql_connector{dsn: config}, nil
+}
+
+type sql_connector struct {
+	dsn *DSN
+
+	mu      sync.Mutex
+	cluster *Cluster
+	session *Session
+}
+
+func (connector *sql_connector) Connect(ctx context.Context) (driver.Conn, error) {
+	connector.mu.Lock()
+	defer connector.mu.Unlock()
+
+	if connector.session == nil {
+		cluster := NewCluster()
+		cluster.SetContactPoints(strings.Join(connector.dsn.Hosts, ","))
+		if connector.dsn.Port != 0 {
+			cluster.SetPort(int64(connector.dsn.Port))
+		}
+
+		session := NewSession()
+		var err error
+		if connector.dsn.Keyspace != "" {
+			err = cluster.ConnectKeyspaceContext(ctx, session, connector.dsn.Keyspace)
+		} else {
+			err = cluster.ConnectContext(ctx, session)
+		}
+		if err != nil {
+			session.Close()
+			cluster.Close()
+			return nil, err
+		}
+		connector.cluster = cluster
+		connector.session = session
+	}
+
+	return &sql_conn{session: connector.session, consistency: connector.dsn.Consistency}, nil
+}
+
+func (connector *sql_connector) Driver() driver.Driver {
+	return &sql_driver{}
+}
+
+func (connector *sql_connector) Close() error {
+	connector.mu.Lock()
+	defer connector.mu.Unlock()
+
+	var err error
+	if connector.session != nil {
+		err = connector.session.Close()
+		connector.session = nil
+	}
+	if connector.cluster != nil {
+		connector.cluster.Close()
+		connector.cluster = nil
+	}
+	return err
+}
+
+type sql_conn struct {
+	session     *Session
+	consistency Consistency
+	owner       *sql_connector
+}
+
+func (conn *sql_conn) Prepare(query string) (driver.Stmt, error) {
+	return conn.PrepareContext(context.Background(), query)
+}
+
+func (conn *sql_conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	prepared, err := conn.session.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &sql_stmt{conn: conn, prepared: prepared}, nil
+}
+
+func (conn *sql_conn) Close() error {
+	if conn.owner != nil {
+		return conn.owner.Close()
+	}
+	return nil
+}
+
+func (conn *sql_conn) Begin() (driver.Tx, error) {
+	return nil, new_error_with_message(C.CASS_ERROR_LIB_NOT_IMPLEMENTED, "transactions are not supported")
+}
+
+// ExecContext and QueryContext run ad hoc queries through the session's
+// prepared statement cache rather than preparing them on every call.
+func (conn *sql_conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	prepared, release, err := conn.session.cached_prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return conn.exec(ctx, prepared, args)
+}
+
+func (conn *sql_conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	prepared, release, err := conn.session.cached_prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return conn.query(ctx, prepared, args)
+}
+
+// CheckNamedValue lets values Statement.Bind understands, such as Uuid and
+// collections, through to it unchanged.
+func (conn *sql_conn) CheckNamedValue(arg *driver.NamedValue) error {
+	switch arg.Value.(type) {
+	case Uuid, Date, CqlDuration, time.Duration, Decimal, *big.Int, *big.Float, UnsetValue:
+		return nil
+	case null_valuer:
+		return nil
+	case []byte:
+		return driver.ErrSkip
+	}
+	if is_collection_value(arg.Value) {
+		return nil
+	}
+	return driver.ErrSkip
+}
+
+func (conn *sql_conn) bind(prepared *Prepared, args []driver.NamedValue) (*Statement, error) {
+	statement := prepared.Bind()
+	conn.session.mark_idempotent(statement)
+	if conn.consistency != CASS_CONSISTENCY_UNKNOWN {
+		if err := statement.SetConsistency(conn.consistency); err != nil {
+			statement.Close()
+			return nil, err
+		}
+	}
+
+	for i, arg := range args {
+		if err := sql_bind(statement, prepared, i, arg); err != nil {
+			statement.Close()
+			return nil, err
+		}
+	}
+	return statement, nil
+}
+
+// sql_bind binds one database/sql argument, by name if it has one and by
+// position otherwise.
+func sql_bind(statement *Statement, prepared *Prepared, index int, arg driver.NamedValue) error {
+	if arg.Name == "" {
+		data_type := C.cass_prepared_parameter_data_type(prepared.cptr, C.size_t(index))
+		value, err := sql_bind_value(data_type, arg.Value)
+		if err != nil {
+			return err
+		}
+		return bind_value(statement, index, value)
+	}
+
+	cs := C.CString(arg.Name)
+	defer C.free(unsafe.Pointer(cs))
+	data_type := C.cass_prepared_parameter_data_type_by_name(prepared.cptr, cs)
+	value, err := sql_bind_value(data_type, arg.Value)
+	if err != nil {
+		return err
+	}
+	return bind_value_by_name(statement, cs, value)
+}
+
+func (conn *sql_conn) exec(ctx context.Context, prepared *Prepared, args []driver.NamedValue) (driver.Result, error) {
+	statement, err := conn.bind(prepared, args)
+	if err != nil {
+		return nil, err
+	}
+	defer statement.Close()
+
+	result, err := conn.session.ExecuteContext(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+	result.Close()
+	return driver.ResultNoRows, nil
+}
+
+func (conn *sql_conn) query(ctx context.Context, prepared *Prepared, args []driver.NamedValue) (driver.Rows, error) {
+	statement, err := conn.bind(prepared, args)
+	if err != nil {
+		return nil, err
+	}
+
+	iter := conn.session.IterContext(ctx, statement)
+	if iter.Err() == nil {
+		iter.fetch()
+	}
+	if err := iter.Err(); err != nil {
+		iter.Close()
+		statement.Close()
+		return nil, err
+	}
+	return &sql_rows{iter: iter, statement: statement}, nil
+}
+
+// sql_bind_value converts the handful of types database/sql passes to
+// drivers into the ones the prepared statement's parameter expects.
+func sql_bind_value(data_type *C.CassDataType, v interface{}) (interface{}, error) {
+	v = bind_arg(v)
+	if data_type == nil {
+		return v, nil
+	}
+	return sql_convert(int(C.cass_data_type_type(data_type)), v)
+}
+
+// sql_convert turns v, one of the types database/sql passes to drivers,
+// into what Bind needs for a parameter of type vtype.
+func sql_convert(vtype int, v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case int64:
+		switch vtype {
+		case CASS_VALUE_TYPE_TIME:
+			return time.Duration(x), nil
+		case CASS_VALUE_TYPE_INT:
+			if x < math.MinInt32 || x > math.MaxInt32 {
+				return nil, out_of_range("int", x)
+			}
+			return int32(x), nil
+		case CASS_VALUE_TYPE_SMALL_INT:
+			if x < math.MinInt16 || x > math.MaxInt16 {
+				return nil, out_of_range("smallint", x)
+			}
+			return int16(x), nil
+		case CASS_VALUE_TYPE_TINY_INT:
+			if x < math.MinInt8 || x > math.MaxInt8 {
+				return nil, out_of_range("tinyint", x)
+			}
+			return int8(x), nil
+		}
+	case float64:
+		if vtype == CASS_VALUE_TYPE_FLOAT {
+			return float32(x), nil
+		}
+	case time.Time:
+		if vtype == CASS_VALUE_TYPE_DATE {
+			return DateOf(x), nil
+		}
+	case string:
+		switch vtype {
+		case CASS_VALUE_TYPE_DURATION:
+			return ParseCqlDuration(x)
+		case CASS_VALUE_TYPE_UUID, CASS_VALUE_TYPE_TIMEUUID:
+			return ParseUuid(x)
+		case CASS_VALUE_TYPE_INET:
+			ip := net.ParseIP(x)
+			if ip == nil {
+				return nil, bad_params("invalid inet: " + x)
+			}
+			return ip, nil
+		}
+	}
+	return v, nil
+}
+
+func out_of_range(cql_type string, x int64) error {
+	return new_error_with_message(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE, "value out of range for "+cql_type+": "+strconv.FormatInt(x, 10))
+}
+
+type sql_stmt struct {
+	conn     *sql_conn
+	prepared *Prepared
+}
+
+func (stmt *sql_stmt) Close() error {
+	return stmt.prepared.Close()
+}
+
+func (stmt *sql_stmt) NumInput() int {
+	return -1
+}
+
+func (stmt *sql_stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return stmt.ExecContext(context.Background(), named_values(args))
+}
+
+func (stmt *sql_stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return stmt.QueryContext(context.Background(), named_values(args))
+}
+
+func (stmt *sql_stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return stmt.conn.exec(ctx, stmt.prepared, args)
+}
+
+func (stmt *sql_stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return stmt.conn.query(ctx, stmt.prepared, args)
+}
+
+func (stmt *sql_stmt) CheckNamedValue(arg *driver.NamedValue) error {
+	return stmt.conn.CheckNamedValue(arg)
+}
+
+func named_values(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return named
+}
+
+// sql_rows reads every page of the result, not only the first.
+type sql_rows struct {
+	iter      *Iter
+	statement *Statement
+	columns   []string
+}
+
+func (rows *sql_rows) Columns() []string {
+	if rows.columns == nil {
+		result := rows.iter.Result()
+		count := result.ColumnCount()
+		rows.columns = make([]string, count)
+		for i := uint64(0); i < count; i++ {
+			rows.columns[i] = result.ColumnName(i)
+		}
+	}
+	return rows.columns
+}
+
+func (rows *sql_rows) ColumnTypeDatabaseTypeName(index int) string {
+	return strings.ToUpper(rows.iter.Result().ColumnDataType(uint64(index)).String())
+}
+
+func (rows *sql_rows) Close() error {
+	rows.iter.Close()
+	return rows.statement.Close()
+}
+
+func (rows *sql_rows) Next(dest []driver.Value) error {
+	if !rows.iter.Next() {
+		if err := rows.iter.Err(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+
+	row := C.cass_iterator_get_row(rows.iter.Result().iter)
+	for i := range dest {
+		value, err := driver_value(C.cass_row_get_column(row, C.size_t(i)))
+		if err != nil {
+			return err
+		}
+		dest[i] = value
+	}
+	return nil
+}
+
+func driver_value(value *C.CassValue) (driver.Value, error) {
+	if C.cass_value_is_null(value) != 0 {
+		return nil, nil
+	}
+
+	switch C.cass_value_type(value) {
+	case C.CASS_VALUE_TYPE_TINY_INT:
+		var v int8
+		err := scan_value(value, &v)
+		return int64(v), err
+
+	case C.CASS_VALUE_TYPE_SMALL_INT:
+		var v int16
+		err := scan_value(value, &v)
+		return int64(v), err
+
+	case C.CASS_VALUE_TYPE_INT:
+		var v int32
+		err := scan_value(value, &v)
+		return int64(v), err
+
+	case C.CASS_VALUE_TYPE_BIGINT, C.CASS_VALUE_TYPE_COUNTER:
+		var v int64
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_FLOAT:
+		var v float32
+		err := scan_value(value, &v)
+		return float64(v), err
+
+	case C.CASS_VALUE_TYPE_DOUBLE:
+		var v float64
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_BOOLEAN:
+		var v bool
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_ASCII, C.CASS_VALUE_TYPE_TEXT, C.CASS_VALUE_TYPE_VARCHAR:
+		var v string
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_BLOB, C.CASS_VALUE_TYPE_CUSTOM:
+		var v []byte
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_TIMESTAMP, C.CASS_VALUE_TYPE_DATE:
+		var v time.Time
+		err := scan_value(value, &v)
+		return v, err
+
+	case C.CASS_VALUE_TYPE_TIME:
+		var v time.Duration
+		err := scan_value(value, &v)
+		return int64(v), err
+
+	case C.CASS_VALUE_TYPE_DURATION:
+		var v CqlDuration
+		err := scan_value(value, &v)
+		return v.String(), err
+
+	case C.CASS_VALUE_TYPE_VARINT:
+		v := new(big.Int)
+		err := scan_value(value, v)
+		return v.String(), err
+
+	case C.CASS_VALUE_TYPE_DECIMAL:
+		var v Decimal
+		err := scan_value(value, &v)
+		return v.String(), err
+
+	case C.CASS_VALUE_TYPE_UUID, C.CASS_VALUE_TYPE_TIMEUUID:
+		var uuid Uuid
+		if rc := C.cass_value_get_uuid(value, &uuid.uuid); rc != C.CASS_OK {
+			return nil, new_error(rc)
+		}
+		return uuid.String(), nil
+
+	case C.CASS_VALUE_TYPE_INET:
+		var inet C.CassInet
+		if rc := C.cass_value_get_inet(value, &inet); rc != C.CASS_OK {
+			return nil, new_error(rc)
+		}
+		var buf [C.CASS_INET_STRING_LENGTH]C.char
+		C.cass_inet_string(inet, &buf[0])
+		return C.GoString(&buf[0]), nil
+	}
+
+	return nil, new_error_with_message(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE,
+		"unsupported column type in database/sql: "+ValueTypeName(int(C.cass_value_type(value))))
+}
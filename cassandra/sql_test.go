@@ -0,0 +1,96 @@
+package cassandra
+
+import "net"
+import "reflect"
+import "testing"
+import "time"
+
+func TestParseDSN(t *testing.T) {
+	tests := []struct {
+		dsn  string
+		want *DSN
+	}{
+		{"cassandra://host1,host2:9042/shop?consistency=quorum", &DSN{
+			Hosts: []string{"host1", "host2"}, Port: 9042, Keyspace: "shop", Consistency: CASS_CONSISTENCY_QUORUM,
+		}},
+		{"host1", &DSN{Hosts: []string{"host1"}, Consistency: CASS_CONSISTENCY_UNKNOWN}},
+		{"host1,host2/shop", &DSN{Hosts: []string{"host1", "host2"}, Keyspace: "shop", Consistency: CASS_CONSISTENCY_UNKNOWN}},
+		{"cassandra://host1?consistency=one&consistency=local_quorum", &DSN{
+			Hosts: []string{"host1"}, Consistency: CASS_CONSISTENCY_LOCAL_QUORUM,
+		}},
+
+		{"", nil},
+		{"cassandra://", nil},
+		{"cassandra:///shop", nil},
+		{"mysql://host1", nil},
+		{"cassandra://host1:port", nil},
+		{"cassandra://host1?consistency=most", nil},
+		{"cassandra://host1?timeout=5s", nil},
+		{"cassandra://host1:9042%zz", nil},
+	}
+
+	for _, test := range tests {
+		got, err := ParseDSN(test.dsn)
+		if test.want == nil {
+			if err == nil {
+				t.Errorf("ParseDSN(%q) = %+v, want an error", test.dsn, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDSN(%q): %v", test.dsn, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseDSN(%q) = %+v, want %+v", test.dsn, got, test.want)
+		}
+	}
+}
+
+func TestSqlConvert(t *testing.T) {
+	tests := []struct {
+		vtype int
+		v     interface{}
+		want  interface{}
+	}{
+		{CASS_VALUE_TYPE_INT, int64(-5), int32(-5)},
+		{CASS_VALUE_TYPE_SMALL_INT, int64(-32768), int16(-32768)},
+		{CASS_VALUE_TYPE_TINY_INT, int64(127), int8(127)},
+		{CASS_VALUE_TYPE_BIGINT, int64(1 << 40), int64(1 << 40)},
+		{CASS_VALUE_TYPE_TIME, int64(1500), time.Duration(1500)},
+		{CASS_VALUE_TYPE_FLOAT, 1.5, float32(1.5)},
+		{CASS_VALUE_TYPE_INET, "10.0.0.1", net.ParseIP("10.0.0.1")},
+		{CASS_VALUE_TYPE_INET, "::1", net.ParseIP("::1")},
+		{CASS_VALUE_TYPE_DURATION, "1d", CqlDuration{Days: 1}},
+		{CASS_VALUE_TYPE_TEXT, "10.0.0.1", "10.0.0.1"},
+	}
+	for _, test := range tests {
+		got, err := sql_convert(test.vtype, test.v)
+		if err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("sql_convert(%s, %#v) = %#v, %v, want %#v", ValueTypeName(test.vtype), test.v, got, err, test.want)
+		}
+	}
+
+	for _, vtype := range []int{CASS_VALUE_TYPE_UUID, CASS_VALUE_TYPE_TIMEUUID} {
+		if got, err := sql_convert(vtype, "550e8400-e29b-41d4-a716-446655440000"); err != nil {
+			t.Errorf("sql_convert(%s): %v", ValueTypeName(vtype), err)
+		} else if _, ok := got.(Uuid); !ok {
+			t.Errorf("sql_convert(%s) = %T, want Uuid", ValueTypeName(vtype), got)
+		}
+	}
+
+	failures := []struct {
+		vtype int
+		v     interface{}
+	}{
+		{CASS_VALUE_TYPE_INT, int64(1 << 31)},
+		{CASS_VALUE_TYPE_SMALL_INT, int64(32768)},
+		{CASS_VALUE_TYPE_TINY_INT, int64(-129)},
+		{CASS_VALUE_TYPE_INET, "10.0.0"},
+	}
+	for _, test := range failures {
+		if got, err := sql_convert(test.vtype, test.v); err == nil {
+			t.Errorf("sql_convert(%s, %#v) = %#v, want an error", ValueTypeName(test.vtype), test.v, got)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package cassandra
+
+// #include <cassandra.h>
+import "C"
+import "crypto/tls"
+import "crypto/x509"
+import "encoding/pem"
+import "unsafe"
+
+type SSLVerify int
+
+const (
+	CASS_SSL_VERIFY_PEER_CERT         SSLVerify = 0x01
+	CASS_SSL_VERIFY_PEER_IDENTITY     SSLVerify = 0x02
+	CASS_SSL_VERIFY_PEER_IDENTITY_DNS SSLVerify = 0x04
+)
+
+// ssl_verify_none turns verification off. It is not exported because
+// Verify cannot ask for it; only InsecureSkipVerify can.
+const ssl_verify_none SSLVerify = 0x00
+
+// SSLConfig describes the SSL settings of a cluster. Certificates and keys
+// are PEM, as written by encoding/pem, or raw DER as found in
+// x509.Certificate.Raw; either way they come from memory rather than files.
+type SSLConfig struct {
+	// TrustedCerts are the CA certificates used to verify the server. Each
+	// entry may hold a bundle of several PEM certificates.
+	TrustedCerts [][]byte
+
+	// Cert and Key are the client certificate and private key for mutual
+	// TLS. KeyPassword decrypts an encrypted key.
+	Cert        []byte
+	Key         []byte
+	KeyPassword string
+
+	// Verify selects how the server's certificate is checked. The chain
+	// is always checked against TrustedCerts, as the driver does by
+	// default; add CASS_SSL_VERIFY_PEER_IDENTITY to check the host's IP
+	// address too, or CASS_SSL_VERIFY_PEER_IDENTITY_DNS to check its name.
+	// Verify cannot turn checking off; see InsecureSkipVerify.
+	Verify SSLVerify
+
+	// InsecureSkipVerify accepts any server certificate. It overrides
+	// Verify and should only be used for testing.
+	InsecureSkipVerify bool
+}
+
+// AddTrustedCert adds cert to the certificates used to verify the server.
+func (config *SSLConfig) AddTrustedCert(cert *x509.Certificate) {
+	config.TrustedCerts = append(config.TrustedCerts, cert.Raw)
+}
+
+// SetClientCertificate uses cert, as loaded by tls.X509KeyPair or
+// tls.LoadX509KeyPair, as the client certificate and key.
+func (config *SSLConfig) SetClientCertificate(cert tls.Certificate) error {
+	var chain []byte
+	for _, der := range cert.Certificate {
+		chain = append(chain, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		return new_error_with_message(C.CASS_ERROR_SSL_INVALID_PRIVATE_KEY, err.Error())
+	}
+
+	config.Cert = chain
+	config.Key = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key})
+	config.KeyPassword = ""
+	return nil
+}
+
+// SetSSL enables SSL on every connection the cluster makes.
+func (cluster *Cluster) SetSSL(config *SSLConfig) error {
+	if config == nil {
+		return bad_params("nil SSL config")
+	}
+
+	ssl := C.cass_ssl_new()
+	// The cluster keeps its own reference.
+	defer C.cass_ssl_free(ssl)
+
+	for _, bundle := range config.TrustedCerts {
+		for _, cert := range pem_blocks(bundle, "CERTIFICATE") {
+			rc := C.cass_ssl_add_trusted_cert_n(ssl, bytes_char_ptr(cert), C.size_t(len(cert)))
+			if rc != C.CASS_OK {
+				return new_error(rc)
+			}
+		}
+	}
+
+	if len(config.Cert) > 0 {
+		var chain []byte
+		for _, cert := range pem_blocks(config.Cert, "CERTIFICATE") {
+			chain = append(chain, cert...)
+		}
+		rc := C.cass_ssl_set_cert_n(ssl, bytes_char_ptr(chain), C.size_t(len(chain)))
+		if rc != C.CASS_OK {
+			return new_error(rc)
+		}
+	}
+
+	if len(config.Key) > 0 {
+		keys := pem_blocks(config.Key, der_key_type(config.Key))
+		if len(keys) != 1 {
+			return new_error(C.CASS_ERROR_SSL_INVALID_PRIVATE_KEY)
+		}
+		password := []byte(config.KeyPassword)
+		rc := C.cass_ssl_set_private_key_n(ssl, bytes_char_ptr(keys[0]), C.size_t(len(keys[0])),
+			bytes_char_ptr(password), C.size_t(len(password)))
+		if rc != C.CASS_OK {
+			return new_error(rc)
+		}
+	}
+
+	C.cass_ssl_set_verify_flags(ssl, C.int(config.verify_flags()))
+	C.cass_cluster_set_ssl(cluster.cptr, ssl)
+	return nil
+}
+
+func (config *SSLConfig) verify_flags() SSLVerify {
+	if config.InsecureSkipVerify {
+		return ssl_verify_none
+	}
+	return config.Verify | CASS_SSL_VERIFY_PEER_CERT
+}
+
+// pem_blocks splits data into separate PEM blocks. Data that holds no PEM
+// at all is taken to be DER and wrapped in a block of block_type.
+func pem_blocks(data []byte, block_type string) [][]byte {
+	var blocks [][]byte
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, pem.EncodeToMemory(block))
+	}
+	if len(blocks) == 0 && len(data) > 0 {
+		blocks = append(blocks, pem.EncodeToMemory(&pem.Block{Type: block_type, Bytes: data}))
+	}
+	return blocks
+}
+
+// der_key_type guesses the PEM block type of a DER private key.
+func der_key_type(der []byte) string {
+	if _, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return "RSA PRIVATE KEY"
+	}
+	if _, err := x509.ParseECPrivateKey(der); err == nil {
+		return "EC PRIVATE KEY"
+	}
+	return "PRIVATE KEY"
+}
+
+func bytes_char_ptr(b []byte) *C.char {
+	return (*C.char)(unsafe.Pointer(bytes_ptr(b)))
+}
@@ -0,0 +1,22 @@
+package cassandra
+
+import "testing"
+
+func TestVerifyFlags(t *testing.T) {
+	tests := []struct {
+		name   string
+		config SSLConfig
+		want   SSLVerify
+	}{
+		{"default", SSLConfig{}, CASS_SSL_VERIFY_PEER_CERT},
+		{"identity", SSLConfig{Verify: CASS_SSL_VERIFY_PEER_IDENTITY}, CASS_SSL_VERIFY_PEER_CERT | CASS_SSL_VERIFY_PEER_IDENTITY},
+		{"dns", SSLConfig{Verify: CASS_SSL_VERIFY_PEER_IDENTITY_DNS}, CASS_SSL_VERIFY_PEER_CERT | CASS_SSL_VERIFY_PEER_IDENTITY_DNS},
+		{"insecure", SSLConfig{Verify: CASS_SSL_VERIFY_PEER_IDENTITY, InsecureSkipVerify: true}, ssl_verify_none},
+	}
+
+	for _, test := range tests {
+		if got := test.config.verify_flags(); got != test.want {
+			t.Errorf("%s: verify_flags() = %#x, want %#x", test.name, got, test.want)
+		}
+	}
+}
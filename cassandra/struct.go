@@ -0,0 +1,316 @@
+package cassandra
+
+// #include <cassandra.h>
+import "C"
+import "reflect"
+import "strings"
+import "sync"
+
+// Struct fields map to bind markers and result columns by name. The name
+// is taken from the field's cql tag, or is the lower-cased field name if
+// there is no tag:
+//
+//	type User struct {
+//		ID    Uuid   `cql:"id"`
+//		Email string `cql:"email,omitempty"`
+//		Audit        // fields of embedded structs are promoted
+//		Temp  string `cql:"-"`
+//	}
+//
+// omitempty leaves a zero-valued field unbound when binding. As with
+// encoding/json, a name used at several depths refers to the shallowest
+// field, and a name shared by several fields at the same depth is ignored
+// unless exactly one of them is tagged.
+
+type struct_field struct {
+	name      string
+	index     []int
+	omitempty bool
+	tagged    bool
+}
+
+type struct_info struct {
+	fields []struct_field
+	byname map[string]*struct_field
+}
+
+var struct_cache sync.Map
+
+func get_struct_info(t reflect.Type) *struct_info {
+	if info, ok := struct_cache.Load(t); ok {
+		return info.(*struct_info)
+	}
+
+	all := collect_fields(t)
+	byname := make(map[string][]struct_field)
+	for _, field := range all {
+		byname[field.name] = append(byname[field.name], field)
+	}
+
+	info := &struct_info{byname: make(map[string]*struct_field)}
+	for _, field := range all {
+		if winner, ok := dominant_field(byname[field.name]); ok && &winner.index[0] == &field.index[0] {
+			info.fields = append(info.fields, field)
+		}
+	}
+	for i := range info.fields {
+		info.byname[info.fields[i].name] = &info.fields[i]
+	}
+
+	actual, _ := struct_cache.LoadOrStore(t, info)
+	return actual.(*struct_info)
+}
+
+// collect_fields lists the fields of t, including those promoted from
+// embedded structs. Like encoding/json it walks the embedded structs
+// breadth-first, so fields come out in order of depth.
+func collect_fields(t reflect.Type) []struct_field {
+	type embedded_struct struct {
+		t     reflect.Type
+		index []int
+	}
+
+	var fields []struct_field
+	visited := make(map[reflect.Type]bool)
+	next := []embedded_struct{{t: t}}
+
+	for len(next) > 0 {
+		level := next
+		next = nil
+		for _, s := range level {
+			// A struct met again deeper down only adds shadowed fields.
+			if visited[s.t] {
+				continue
+			}
+			for i := 0; i < s.t.NumField(); i++ {
+				field := s.t.Field(i)
+				tag, has_tag := field.Tag.Lookup("cql")
+				if tag == "-" {
+					continue
+				}
+
+				index := append(append([]int(nil), s.index...), i)
+
+				if field.Anonymous && !has_tag {
+					embedded := field.Type
+					if embedded.Kind() == reflect.Ptr && embedded.Elem().Kind() == reflect.Struct {
+						// A nil pointer to an unexported struct cannot be
+						// allocated through reflection, so its fields are
+						// out of reach.
+						if field.PkgPath != "" {
+							continue
+						}
+						embedded = embedded.Elem()
+					}
+					if embedded.Kind() == reflect.Struct {
+						next = append(next, embedded_struct{t: embedded, index: index})
+						continue
+					}
+				}
+				if field.PkgPath != "" {
+					continue
+				}
+
+				f := struct_field{name: strings.ToLower(field.Name), index: index}
+				if has_tag {
+					parts := strings.Split(tag, ",")
+					if parts[0] != "" {
+						f.name = parts[0]
+						f.tagged = true
+					}
+					for _, option := range parts[1:] {
+						if option == "omitempty" {
+							f.omitempty = true
+						}
+					}
+				}
+				fields = append(fields, f)
+			}
+		}
+		for _, s := range level {
+			visited[s.t] = true
+		}
+	}
+
+	return fields
+}
+
+// dominant_field picks the field a name refers to among the fields that
+// share it, following Go's rules for promoted fields: the shallowest wins,
+// with a tagged field preferred to untagged ones at the same depth. Any
+// other tie leaves the name unmapped.
+func dominant_field(fields []struct_field) (struct_field, bool) {
+	depth := len(fields[0].index)
+	var candidates []struct_field
+	for _, field := range fields {
+		if len(field.index) == depth {
+			candidates = append(candidates, field)
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+
+	var tagged []struct_field
+	for _, field := range candidates {
+		if field.tagged {
+			tagged = append(tagged, field)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return struct_field{}, false
+}
+
+func struct_value(v interface{}, caller string) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, new_error_with_message(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE,
+			"unsupported type in "+caller+": "+reflect.TypeOf(v).String())
+	}
+	return rv, nil
+}
+
+// field_value returns the field at index, allocating nil embedded struct
+// pointers on the way when alloc is set. It returns an invalid Value if it
+// meets a nil pointer it may not allocate.
+func field_value(v reflect.Value, index []int, alloc bool) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// BindStruct binds the fields of v, a struct or pointer to struct, to the
+// bind markers of a prepared statement with the same names. Markers with no
+// matching field are left unbound.
+func (statement *Statement) BindStruct(v interface{}) error {
+	rv, err := struct_value(v, "BindStruct")
+	if err != nil {
+		return err
+	}
+	if statement.prepared == nil {
+		return new_error_with_message(C.CASS_ERROR_LIB_INVALID_STATEMENT_TYPE, "BindStruct requires a prepared statement")
+	}
+	info := get_struct_info(rv.Type())
+
+	for i := 0; ; i++ {
+		var name *C.char
+		var length C.size_t
+		rc := C.cass_prepared_parameter_name(statement.prepared.cptr, C.size_t(i), &name, &length)
+		if rc == C.CASS_ERROR_LIB_INDEX_OUT_OF_BOUNDS {
+			break
+		}
+		if rc != C.CASS_OK {
+			return new_error(rc)
+		}
+
+		field, ok := info.byname[C.GoStringN(name, C.int(length))]
+		if !ok {
+			continue
+		}
+		fv := field_value(rv, field.index, false)
+		if !fv.IsValid() || field.omitempty && fv.IsZero() {
+			continue
+		}
+		if err := bind_value(statement, i, fv.Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ScanStruct copies the current row into the fields of the struct v points
+// to, matching columns to fields by name. Columns with no matching field are
+// skipped.
+func (result *Result) ScanStruct(v interface{}) error {
+	if err := result.on_row(); err != nil {
+		return err
+	}
+	if reflect.TypeOf(v) == nil || reflect.TypeOf(v).Kind() != reflect.Ptr {
+		return new_error_with_message(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE, "ScanStruct requires a pointer")
+	}
+	rv, err := struct_value(v, "ScanStruct")
+	if err != nil {
+		return err
+	}
+	return result.scan_struct(rv, result.struct_columns(rv.Type()))
+}
+
+// ScanAll reads the remaining rows of the result into the slice v points
+// to, which may hold structs or pointers to structs.
+func (result *Result) ScanAll(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return new_error_with_message(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE, "ScanAll requires a pointer to a slice")
+	}
+	slice := rv.Elem()
+
+	elem_type := slice.Type().Elem()
+	is_ptr := elem_type.Kind() == reflect.Ptr
+	if is_ptr {
+		elem_type = elem_type.Elem()
+	}
+	if elem_type.Kind() != reflect.Struct {
+		return new_error_with_message(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE, "unsupported type in ScanAll: "+slice.Type().String())
+	}
+
+	fields := result.struct_columns(elem_type)
+	for result.Next() {
+		elem := reflect.New(elem_type)
+		if err := result.scan_struct(elem.Elem(), fields); err != nil {
+			return err
+		}
+		if is_ptr {
+			slice = reflect.Append(slice, elem)
+		} else {
+			slice = reflect.Append(slice, elem.Elem())
+		}
+	}
+	rv.Elem().Set(slice)
+	return nil
+}
+
+// struct_columns returns, for each result column, the struct field it
+// maps to, or nil.
+func (result *Result) struct_columns(t reflect.Type) []*struct_field {
+	info := get_struct_info(t)
+	count := result.ColumnCount()
+	fields := make([]*struct_field, count)
+	for i := uint64(0); i < count; i++ {
+		fields[i] = info.byname[result.ColumnName(i)]
+	}
+	return fields
+}
+
+func (result *Result) scan_struct(rv reflect.Value, fields []*struct_field) error {
+	row := C.cass_iterator_get_row(result.iter)
+	for i, field := range fields {
+		if field == nil {
+			continue
+		}
+		fv := field_value(rv, field.index, true)
+		if !fv.IsValid() {
+			continue
+		}
+		value := C.cass_row_get_column(row, C.size_t(i))
+		if err := scan_value(value, fv.Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
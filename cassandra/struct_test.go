@@ -0,0 +1,116 @@
+package cassandra
+
+import "reflect"
+import "testing"
+
+type audit struct {
+	ID      int
+	Created string
+}
+
+type Audit struct {
+	ID      int
+	Created string
+}
+
+type Owner struct {
+	Name string
+}
+
+type Author struct {
+	Name string
+}
+
+type TaggedAuthor struct {
+	Name string `cql:"name"`
+}
+
+type shadowed struct {
+	Audit
+	ID int
+}
+
+type tied struct {
+	Owner
+	Author
+	ID int
+}
+
+type tag_breaks_tie struct {
+	Owner
+	TaggedAuthor
+}
+
+type deeper_loses struct {
+	Audit
+	Inner struct{ Name string } `cql:"-"`
+	Owner
+}
+
+type unexported_pointer struct {
+	*audit
+	Name string
+}
+
+type unexported_value struct {
+	audit
+	Name string
+}
+
+type exported_pointer struct {
+	*Audit
+	Name string
+}
+
+func TestStructInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		want map[string][]int
+	}{
+		{"shadowing", shadowed{}, map[string][]int{"id": {1}, "created": {0, 1}}},
+		{"tie", tied{}, map[string][]int{"id": {2}}},
+		{"tag breaks tie", tag_breaks_tie{}, map[string][]int{"name": {1, 0}}},
+		{"skipped field", deeper_loses{}, map[string][]int{"id": {0, 0}, "created": {0, 1}, "name": {2, 0}}},
+		{"unexported pointer", unexported_pointer{}, map[string][]int{"name": {1}}},
+		{"unexported value", unexported_value{}, map[string][]int{"id": {0, 0}, "created": {0, 1}, "name": {1}}},
+		{"exported pointer", exported_pointer{}, map[string][]int{"id": {0, 0}, "created": {0, 1}, "name": {1}}},
+	}
+
+	for _, test := range tests {
+		info := get_struct_info(reflect.TypeOf(test.v))
+		got := make(map[string][]int)
+		for name, field := range info.byname {
+			got[name] = field.index
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: fields = %v, want %v", test.name, got, test.want)
+		}
+		if len(info.fields) != len(info.byname) {
+			t.Errorf("%s: %d fields but %d names", test.name, len(info.fields), len(info.byname))
+		}
+	}
+}
+
+func TestFieldValue(t *testing.T) {
+	var p exported_pointer
+	v := reflect.ValueOf(&p).Elem()
+
+	if f := field_value(v, []int{0, 0}, false); f.IsValid() {
+		t.Errorf("field_value through nil pointer without alloc = %v, want invalid", f)
+	}
+	f := field_value(v, []int{0, 0}, true)
+	if !f.IsValid() || p.Audit == nil {
+		t.Fatalf("field_value with alloc did not allocate the embedded pointer")
+	}
+	f.SetInt(7)
+	if p.ID != 7 {
+		t.Errorf("ID = %d, want 7", p.ID)
+	}
+
+	var u unexported_pointer
+	v = reflect.ValueOf(&u).Elem()
+	if f := field_value(v, []int{0, 0}, true); f.IsValid() {
+		t.Errorf("field_value through unexported nil pointer = %v, want invalid", f)
+	}
+}
@@ -0,0 +1,239 @@
+package cassandra
+
+// #include <cassandra.h>
+import "C"
+import "encoding"
+import "fmt"
+import "math"
+import "strconv"
+import "strings"
+import "time"
+
+// CQL's temporal types map to Go as follows:
+//
+//	timestamp  time.Time, in UTC to millisecond precision
+//	date       Date
+//	time       time.Duration since midnight, to nanosecond precision
+//	duration   CqlDuration
+//
+// Scanning a date into a time.Time gives midnight UTC of that day.
+
+var (
+	_ encoding.TextMarshaler   = Date{}
+	_ encoding.TextUnmarshaler = (*Date)(nil)
+	_ encoding.TextMarshaler   = CqlDuration{}
+	_ encoding.TextUnmarshaler = (*CqlDuration)(nil)
+)
+
+// Date is a calendar day with no time zone, as stored in a date column.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the day t falls on in its own location.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// ParseDate parses a date in the form "2006-01-02".
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return Date{}, bad_params("invalid date: " + s)
+	}
+	return DateOf(t), nil
+}
+
+// In returns midnight at the start of the day in loc.
+func (date Date) In(loc *time.Location) time.Time {
+	return time.Date(date.Year, date.Month, date.Day, 0, 0, 0, 0, loc)
+}
+
+func (date Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", date.Year, int(date.Month), date.Day)
+}
+
+func (date Date) MarshalText() ([]byte, error) {
+	return []byte(date.String()), nil
+}
+
+func (date *Date) UnmarshalText(text []byte) error {
+	parsed, err := ParseDate(string(text))
+	if err != nil {
+		return err
+	}
+	*date = parsed
+	return nil
+}
+
+// Dates are sent as days since the Unix epoch, offset by 2^31 so the value
+// is unsigned.
+const date_epoch = 1 << 31
+
+func (date Date) cass_date() C.cass_uint32_t {
+	days := date.In(time.UTC).Unix() / (24 * 60 * 60)
+	return C.cass_uint32_t(days + date_epoch)
+}
+
+func new_date(v C.cass_uint32_t) Date {
+	days := int64(v) - date_epoch
+	return DateOf(time.Unix(days*24*60*60, 0).UTC())
+}
+
+// CqlDuration is a value of a CQL duration column. Months and days are kept
+// apart from the rest because their length varies.
+type CqlDuration struct {
+	Months int32
+	Days   int32
+	Nanos  int64
+}
+
+// duration_units are the units of a CQL duration literal that fit in Nanos,
+// largest first.
+var duration_units = []struct {
+	name string
+	size int64
+}{
+	{"h", int64(time.Hour)},
+	{"m", int64(time.Minute)},
+	{"s", int64(time.Second)},
+	{"ms", int64(time.Millisecond)},
+	{"us", int64(time.Microsecond)},
+	{"ns", 1},
+}
+
+// String formats the duration as a CQL duration literal such as
+// "1mo2d3h4m5s", which ParseCqlDuration and CQL itself accept. The three
+// parts of a valid duration share a sign, which is written once in front.
+func (duration CqlDuration) String() string {
+	months, days := int64(duration.Months), int64(duration.Days)
+	// Nanos is made unsigned before negating, since -math.MinInt64 does
+	// not fit in an int64.
+	nanos := uint64(duration.Nanos)
+	var b strings.Builder
+	if months < 0 || days < 0 || duration.Nanos < 0 {
+		b.WriteByte('-')
+		months, days, nanos = -months, -days, -nanos
+	}
+	if months != 0 {
+		fmt.Fprintf(&b, "%dmo", months)
+	}
+	if days != 0 {
+		fmt.Fprintf(&b, "%dd", days)
+	}
+	for _, unit := range duration_units {
+		if n := nanos / uint64(unit.size); n != 0 {
+			fmt.Fprintf(&b, "%d%s", n, unit.name)
+			nanos %= uint64(unit.size)
+		}
+	}
+	if b.Len() == 0 || b.String() == "-" {
+		return "0s"
+	}
+	return b.String()
+}
+
+// ParseCqlDuration parses a CQL duration literal in the standard format,
+// such as "1y2mo", "-3d12h" or "90s". The units are y, mo, w, d, h, m, s,
+// ms, us (or µs) and ns.
+func ParseCqlDuration(s string) (CqlDuration, error) {
+	invalid := bad_params("invalid duration: " + s)
+
+	rest := s
+	negative := strings.HasPrefix(rest, "-")
+	if negative {
+		rest = rest[1:]
+	}
+	if rest == "" {
+		return CqlDuration{}, invalid
+	}
+
+	// The parts are summed as magnitudes, which for a negative duration
+	// may reach one more than the largest positive value.
+	max_days, max_nanos := uint64(math.MaxInt32), uint64(math.MaxInt64)
+	if negative {
+		max_days, max_nanos = max_days+1, max_nanos+1
+	}
+	add := func(total *uint64, n, size, max uint64) bool {
+		if n > (max-*total)/size {
+			return false
+		}
+		*total += n * size
+		return true
+	}
+
+	var months, days, nanos uint64
+	for rest != "" {
+		i := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' })
+		if i <= 0 {
+			return CqlDuration{}, invalid
+		}
+		n, err := strconv.ParseUint(rest[:i], 10, 64)
+		if err != nil {
+			return CqlDuration{}, invalid
+		}
+		rest = rest[i:]
+		j := strings.IndexFunc(rest, func(r rune) bool { return '0' <= r && r <= '9' })
+		if j < 0 {
+			j = len(rest)
+		}
+		unit := strings.ToLower(rest[:j])
+		rest = rest[j:]
+
+		ok := false
+		switch unit {
+		case "y":
+			ok = add(&months, n, 12, max_days)
+		case "mo":
+			ok = add(&months, n, 1, max_days)
+		case "w":
+			ok = add(&days, n, 7, max_days)
+		case "d":
+			ok = add(&days, n, 1, max_days)
+		case "µs":
+			unit = "us"
+			fallthrough
+		default:
+			for _, u := range duration_units {
+				if u.name == unit {
+					ok = add(&nanos, n, uint64(u.size), max_nanos)
+				}
+			}
+		}
+		if !ok {
+			return CqlDuration{}, invalid
+		}
+	}
+
+	if negative {
+		months, days, nanos = -months, -days, -nanos
+	}
+	return CqlDuration{Months: int32(months), Days: int32(days), Nanos: int64(nanos)}, nil
+}
+
+func (duration CqlDuration) MarshalText() ([]byte, error) {
+	return []byte(duration.String()), nil
+}
+
+func (duration *CqlDuration) UnmarshalText(text []byte) error {
+	parsed, err := ParseCqlDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*duration = parsed
+	return nil
+}
+
+func timestamp_ms(t time.Time) C.cass_int64_t {
+	return C.cass_int64_t(t.UnixMilli())
+}
+
+func time_of_day(d time.Duration) (C.cass_int64_t, error) {
+	if d < 0 || d >= 24*time.Hour {
+		return 0, new_error_with_message(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE, "time of day out of range: "+d.String())
+	}
+	return C.cass_int64_t(d), nil
+}
@@ -0,0 +1,63 @@
+package cassandra
+
+import "math"
+import "testing"
+import "time"
+
+func TestCqlDurationString(t *testing.T) {
+	tests := []struct {
+		duration CqlDuration
+		want     string
+	}{
+		{CqlDuration{}, "0s"},
+		{CqlDuration{Months: 1, Days: 2, Nanos: int64(3 * time.Hour)}, "1mo2d3h"},
+		{CqlDuration{Nanos: int64(1500 * time.Millisecond)}, "1s500ms"},
+		{CqlDuration{Nanos: int64(time.Hour + 2*time.Minute + 3*time.Microsecond + 4)}, "1h2m3us4ns"},
+		{CqlDuration{Months: -14, Days: -3}, "-14mo3d"},
+		{CqlDuration{Nanos: -int64(90 * time.Second)}, "-1m30s"},
+		{CqlDuration{Nanos: math.MaxInt64}, "2562047h47m16s854ms775us807ns"},
+		{CqlDuration{Nanos: math.MinInt64}, "-2562047h47m16s854ms775us808ns"},
+		{CqlDuration{Months: math.MinInt32, Days: math.MinInt32}, "-2147483648mo2147483648d"},
+	}
+
+	for _, test := range tests {
+		got := test.duration.String()
+		if got != test.want {
+			t.Errorf("%#v.String() = %q, want %q", test.duration, got, test.want)
+		}
+		parsed, err := ParseCqlDuration(got)
+		if err != nil {
+			t.Errorf("ParseCqlDuration(%q): %v", got, err)
+		} else if parsed != test.duration {
+			t.Errorf("ParseCqlDuration(%q) = %#v, want %#v", got, parsed, test.duration)
+		}
+	}
+}
+
+func TestParseCqlDuration(t *testing.T) {
+	tests := []struct {
+		s    string
+		want CqlDuration
+	}{
+		{"1y2mo", CqlDuration{Months: 14}},
+		{"2w3d", CqlDuration{Days: 17}},
+		{"-3d12h", CqlDuration{Days: -3, Nanos: -int64(12 * time.Hour)}},
+		{"10µs", CqlDuration{Nanos: int64(10 * time.Microsecond)}},
+		{"1H30M", CqlDuration{Nanos: int64(90 * time.Minute)}},
+	}
+	for _, test := range tests {
+		got, err := ParseCqlDuration(test.s)
+		if err != nil {
+			t.Errorf("ParseCqlDuration(%q): %v", test.s, err)
+		} else if got != test.want {
+			t.Errorf("ParseCqlDuration(%q) = %#v, want %#v", test.s, got, test.want)
+		}
+	}
+
+	for _, s := range []string{"", "-", "5", "h", "1x", "1.5h", "--1d", "9999999999y",
+		"2147483648mo", "-2147483649d", "9223372036854775808ns", "-9223372036854775809ns", "2562048h"} {
+		if _, err := ParseCqlDuration(s); err == nil {
+			t.Errorf("ParseCqlDuration(%q) succeeded", s)
+		}
+	}
+}
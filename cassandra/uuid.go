@@ -0,0 +1,198 @@
+package cassandra
+
+// #include <stdlib.h>
+// #include <cassandra.h>
+import "C"
+import "database/sql"
+import "encoding"
+import "encoding/json"
+import "time"
+import "unsafe"
+
+var (
+	_ encoding.TextMarshaler   = Uuid{}
+	_ encoding.TextUnmarshaler = (*Uuid)(nil)
+	_ json.Marshaler           = Uuid{}
+	_ json.Unmarshaler         = (*Uuid)(nil)
+	_ sql.Scanner              = (*Uuid)(nil)
+)
+
+// ParseUuid parses the canonical form of a uuid,
+// "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx".
+func ParseUuid(s string) (Uuid, error) {
+	var uuid Uuid
+	cs := C.CString(s)
+	defer C.free(unsafe.Pointer(cs))
+	rc := C.cass_uuid_from_string(cs, &uuid.uuid)
+	if rc != C.CASS_OK {
+		return Uuid{}, new_error_with_message(rc, "invalid uuid: "+s)
+	}
+	return uuid, nil
+}
+
+// UuidFromBytes returns the uuid whose 16 bytes, in network order, are b.
+func UuidFromBytes(b [16]byte) Uuid {
+	var time_and_version, clock_seq_and_node uint64
+	time_and_version = uint64(b[0])<<24 | uint64(b[1])<<16 | uint64(b[2])<<8 | uint64(b[3]) |
+		uint64(b[4])<<40 | uint64(b[5])<<32 |
+		uint64(b[6])<<56 | uint64(b[7])<<48
+	for _, x := range b[8:] {
+		clock_seq_and_node = clock_seq_and_node<<8 | uint64(x)
+	}
+
+	var uuid Uuid
+	uuid.uuid.time_and_version = C.cass_uint64_t(time_and_version)
+	uuid.uuid.clock_seq_and_node = C.cass_uint64_t(clock_seq_and_node)
+	return uuid
+}
+
+// Bytes returns the 16 bytes of the uuid in network order, as stored by
+// Cassandra.
+func (uuid Uuid) Bytes() [16]byte {
+	time_and_version := uint64(uuid.uuid.time_and_version)
+	clock_seq_and_node := uint64(uuid.uuid.clock_seq_and_node)
+
+	var b [16]byte
+	b[0] = byte(time_and_version >> 24)
+	b[1] = byte(time_and_version >> 16)
+	b[2] = byte(time_and_version >> 8)
+	b[3] = byte(time_and_version)
+	b[4] = byte(time_and_version >> 40)
+	b[5] = byte(time_and_version >> 32)
+	b[6] = byte(time_and_version >> 56)
+	b[7] = byte(time_and_version >> 48)
+	for i := 15; i >= 8; i-- {
+		b[i] = byte(clock_seq_and_node)
+		clock_seq_and_node >>= 8
+	}
+	return b
+}
+
+func (uuid Uuid) String() string {
+	var buf [C.CASS_UUID_STRING_LENGTH]C.char
+	C.cass_uuid_string(uuid.uuid, &buf[0])
+	return C.GoString(&buf[0])
+}
+
+// Version returns the uuid's version, read from the same bits as
+// cass_uuid_version without a call into C, since Compare needs it twice.
+func (uuid Uuid) Version() int {
+	return int(uuid.uuid.time_and_version >> 60 & 0x0F)
+}
+
+// The 100ns intervals between the start of the Gregorian calendar, where
+// version 1 timestamps count from, and the Unix epoch.
+const uuid_epoch_offset = 0x01B21DD213814000
+
+// Timestamp returns the time a version 1 uuid (a timeuuid) was generated,
+// to 100ns precision. It returns the zero Time for other versions.
+func (uuid Uuid) Timestamp() time.Time {
+	if uuid.Version() != 1 {
+		return time.Time{}
+	}
+	ticks := int64(uuid.timestamp()) - uuid_epoch_offset
+	return time.Unix(ticks/1e7, ticks%1e7*100).UTC()
+}
+
+func (uuid Uuid) timestamp() uint64 {
+	return uint64(uuid.uuid.time_and_version) & 0x0FFFFFFFFFFFFFFF
+}
+
+// Compare returns -1, 0 or 1 as uuid sorts before, with or after other in
+// a Cassandra uuid or timeuuid column: by version, then by timestamp for
+// timeuuids or by the high bytes for other versions, and finally by the
+// clock sequence and node bytes compared as signed.
+func (uuid Uuid) Compare(other Uuid) int {
+	if v1, v2 := uuid.Version(), other.Version(); v1 != v2 {
+		return compare_uint64(uint64(v1), uint64(v2))
+	}
+
+	if uuid.Version() == 1 {
+		if c := compare_uint64(uuid.timestamp(), other.timestamp()); c != 0 {
+			return c
+		}
+	} else {
+		a, b := uuid.Bytes(), other.Bytes()
+		for i := 0; i < 8; i++ {
+			if c := compare_uint64(uint64(a[i]), uint64(b[i])); c != 0 {
+				return c
+			}
+		}
+	}
+
+	a, b := uuid.Bytes(), other.Bytes()
+	for i := 8; i < 16; i++ {
+		if a[i] != b[i] {
+			if int8(a[i]) < int8(b[i]) {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func compare_uint64(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func (uuid Uuid) MarshalText() ([]byte, error) {
+	return []byte(uuid.String()), nil
+}
+
+func (uuid *Uuid) UnmarshalText(text []byte) error {
+	parsed, err := ParseUuid(string(text))
+	if err != nil {
+		return err
+	}
+	*uuid = parsed
+	return nil
+}
+
+func (uuid Uuid) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + uuid.String() + `"`), nil
+}
+
+// UnmarshalJSON accepts a uuid string, or null for the zero Uuid.
+func (uuid *Uuid) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*uuid = Uuid{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return uuid.UnmarshalText([]byte(s))
+}
+
+// Scan implements sql.Scanner. It accepts the canonical string form, as
+// either a string or []byte, or the 16 raw bytes of the uuid. NULL scans
+// as the zero Uuid.
+func (uuid *Uuid) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*uuid = Uuid{}
+		return nil
+	case string:
+		return uuid.UnmarshalText([]byte(src))
+	case []byte:
+		if len(src) == 16 {
+			var b [16]byte
+			copy(b[:], src)
+			*uuid = UuidFromBytes(b)
+			return nil
+		}
+		return uuid.UnmarshalText(src)
+	case Uuid:
+		*uuid = src
+		return nil
+	}
+	return new_error_with_message(C.CASS_ERROR_LIB_INVALID_VALUE_TYPE, "cannot scan into Uuid")
+}
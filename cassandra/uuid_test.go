@@ -0,0 +1,112 @@
+package cassandra
+
+import "encoding/hex"
+import "encoding/json"
+import "strings"
+import "testing"
+import "time"
+
+// uuid_bytes decodes the canonical form of a uuid without the driver.
+func uuid_bytes(t *testing.T, s string) [16]byte {
+	var b [16]byte
+	raw, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil || len(raw) != 16 {
+		t.Fatalf("bad test uuid %q", s)
+	}
+	copy(b[:], raw)
+	return b
+}
+
+// timeuuid returns a version 1 uuid with the given timestamp and node.
+func timeuuid(ts uint64, node byte) Uuid {
+	var b [16]byte
+	b[0], b[1], b[2], b[3] = byte(ts>>24), byte(ts>>16), byte(ts>>8), byte(ts)
+	b[4], b[5] = byte(ts>>40), byte(ts>>32)
+	b[6], b[7] = byte(ts>>56)&0x0F|0x10, byte(ts>>48)
+	b[8] = 0x80
+	b[15] = node
+	return UuidFromBytes(b)
+}
+
+func TestUuidBytes(t *testing.T) {
+	const canonical = "a4a70900-24e1-11df-8924-001ff3591711"
+	want := [16]byte{
+		0xa4, 0xa7, 0x09, 0x00, 0x24, 0xe1, 0x11, 0xdf,
+		0x89, 0x24, 0x00, 0x1f, 0xf3, 0x59, 0x17, 0x11,
+	}
+	if got := uuid_bytes(t, canonical); got != want {
+		t.Fatalf("test vector mismatch: %x", got)
+	}
+
+	uuid := UuidFromBytes(want)
+	if got := uint64(uuid.uuid.time_and_version); got != 0x11df24e1a4a70900 {
+		t.Errorf("time_and_version = %#x, want %#x", got, uint64(0x11df24e1a4a70900))
+	}
+	if got := uint64(uuid.uuid.clock_seq_and_node); got != 0x8924001ff3591711 {
+		t.Errorf("clock_seq_and_node = %#x, want %#x", got, uint64(0x8924001ff3591711))
+	}
+	if got := uuid.Bytes(); got != want {
+		t.Errorf("Bytes() = %x, want %x", got, want)
+	}
+	if got := uuid.Version(); got != 1 {
+		t.Errorf("Version() = %d, want 1", got)
+	}
+	wall := time.Date(2010, 3, 1, 3, 22, 19, 305600000, time.UTC)
+	if got := uuid.Timestamp(); !got.Equal(wall) {
+		t.Errorf("Timestamp() = %v, want %v", got, wall)
+	}
+}
+
+func TestUuidRoundTrip(t *testing.T) {
+	for _, s := range []string{
+		"00000000-0000-0000-0000-000000000000",
+		"ffffffff-ffff-ffff-ffff-ffffffffffff",
+		"550e8400-e29b-41d4-a716-446655440000",
+		"01234567-89ab-cdef-0123-456789abcdef",
+	} {
+		b := uuid_bytes(t, s)
+		if got := UuidFromBytes(b).Bytes(); got != b {
+			t.Errorf("%s: round trip gave %x", s, got)
+		}
+	}
+}
+
+func TestUuidCompare(t *testing.T) {
+	// Later timestamps sort later even when their low bytes, which come
+	// first in the canonical form, are smaller.
+	earlier := timeuuid(0x1df24e1ffffffff, 0x01)
+	later := timeuuid(0x1df24e200000000, 0x01)
+	if earlier.Compare(later) != -1 || later.Compare(earlier) != 1 {
+		t.Errorf("timeuuids not ordered by timestamp")
+	}
+	if earlier.Compare(earlier) != 0 {
+		t.Errorf("Compare of a uuid with itself != 0")
+	}
+
+	// Equal timestamps fall back to the node bytes, compared as signed.
+	low, high := timeuuid(0x1df24e1a4a70900, 0x7F), timeuuid(0x1df24e1a4a70900, 0x80)
+	if low.Compare(high) != 1 {
+		t.Errorf("node bytes not compared as signed")
+	}
+
+	// Versions sort before timestamps.
+	v4 := UuidFromBytes(uuid_bytes(t, "00000000-0000-4000-8000-000000000000"))
+	if later.Compare(v4) != -1 {
+		t.Errorf("version 1 uuid does not sort before version 4")
+	}
+}
+
+func TestUuidUnmarshalJSONNull(t *testing.T) {
+	uuid := UuidFromBytes(uuid_bytes(t, "550e8400-e29b-41d4-a716-446655440000"))
+	if err := json.Unmarshal([]byte("null"), &uuid); err != nil {
+		t.Fatalf("Unmarshal of null: %v", err)
+	}
+	if uuid != (Uuid{}) {
+		t.Errorf("Unmarshal of null gave %x, want the zero Uuid", uuid.Bytes())
+	}
+
+	var v struct{ ID *Uuid }
+	if err := json.Unmarshal([]byte(`{"ID": null}`), &v); err != nil || v.ID != nil {
+		t.Errorf("Unmarshal of a null field: %v, %v", v.ID, err)
+	}
+}
@@ -8,24 +8,32 @@ import (
 func main() {
 	cluster := cassandra.NewCluster()
 	cluster.SetContactPoints("127.0.0.1")
-	defer cluster.Finalize()
+	defer cluster.Close()
 
 	session := cassandra.NewSession()
-	defer session.Finalize()
+	defer session.Close()
 
 	sessfuture := cluster.SessionConnect(session)
 	sessfuture.Wait()
-	defer sessfuture.Finalize()
+	defer sessfuture.Close()
+	if err := sessfuture.Err(); err != nil {
+		fmt.Printf("Unable to connect: %s\n", err)
+		return
+	}
 
 	statement := cassandra.NewStatement("select cluster_name from system.local;", 0)
-	defer statement.Finalize()
+	defer statement.Close()
 
 	stmtfuture := session.Execute(statement)
 	stmtfuture.Wait()
-	defer stmtfuture.Finalize()
+	defer stmtfuture.Close()
+	if err := stmtfuture.Err(); err != nil {
+		fmt.Printf("Query failed: %s\n", err)
+		return
+	}
 
 	result := stmtfuture.Result()
-	defer result.Finalize()
+	defer result.Close()
 
 	fmt.Printf("Clusters:\r\n")
 	for result.Next() {